@@ -0,0 +1,261 @@
+package fuzzy
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/xml"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// BigIntStyle is the format big integers are encoded with
+type BigIntStyle int
+
+const (
+	// BigIntNumber formats big integers as JSON numbers
+	BigIntNumber BigIntStyle = iota
+	// BigIntString formats big integers as JSON strings
+	BigIntString
+	// BigIntSafe formats big integers as JSON numbers if they are
+	// safe integers in JavaScript, i.e. within ±(2^53-1), otherwise as strings
+	BigIntSafe
+)
+
+// maxSafeInteger is the max integer that is exact in a float64
+var maxSafeInteger = big.NewInt(1<<53 - 1)
+
+// maxBigIntDigits limits the digits of numbers with an exponent,
+// e.g. 1e1000000000 is out of range
+const maxBigIntDigits = 10000
+
+// BigInt can be used to decode any JSON value to an integer
+// of any size, e.g. 18446744073709551616.
+// Numbers with an exponent are decoded exactly, e.g. 1.8e19,
+// and numbers with a fraction will error.
+// Strings that are valid numbers are decoded like numbers,
+// and strings with the 0x prefix are hexadecimal.
+// Bools are 1 and 0 if Options.BoolToNumber is set, otherwise they error.
+// The zero value is 0
+type BigInt struct {
+	// i is never modified, nil for the zero value
+	i *big.Int
+}
+
+// NewBigInt returns a BigInt with the value of x
+func NewBigInt(x *big.Int) BigInt {
+	return BigInt{i: new(big.Int).Set(x)}
+}
+
+// ParseBigInt parses s, a decimal integer, or hexadecimal with the 0x prefix
+func ParseBigInt(s string) (BigInt, error) {
+	v, cErr := decodeBigInt(KindString, []byte(s), &defaultOptions)
+	if cErr != nil {
+		return v, withValue(cErr, (*BigInt)(nil), []byte(s))
+	}
+	return v, nil
+}
+
+// Int returns a copy of the value
+func (fi BigInt) Int() *big.Int {
+	if fi.i == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(fi.i)
+}
+
+// String returns the value formatted as a decimal integer
+func (fi BigInt) String() string {
+	if fi.i == nil {
+		return "0"
+	}
+	return fi.i.String()
+}
+
+// MarshalJSON method for BigInt
+func (fi BigInt) MarshalJSON() ([]byte, error) {
+	return fi.marshalJSON(&defaultOptions)
+}
+
+func (fi BigInt) marshalJSON(opts *Options) ([]byte, error) {
+	return encode(fi, opts)
+}
+
+// UnmarshalJSON method for BigInt
+func (fi *BigInt) UnmarshalJSON(bArr []byte) (err error) {
+	return fi.unmarshalJSON(bArr, &defaultOptions)
+}
+
+func (fi *BigInt) unmarshalJSON(bArr []byte, opts *Options) (err error) {
+	v, _, err := decode[BigInt](bArr, fi, opts)
+	if err != nil {
+		return err
+	}
+	*fi = v
+	return
+}
+
+// MarshalText method for BigInt
+func (fi BigInt) MarshalText() ([]byte, error) {
+	return encodeText(fi), nil
+}
+
+// UnmarshalText method for BigInt
+func (fi *BigInt) UnmarshalText(text []byte) error {
+	return fi.unmarshalText(KindString, text, &defaultOptions)
+}
+
+func (fi *BigInt) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, _, err := decodeText[BigInt](k, text, fi, opts)
+	if err != nil {
+		return err
+	}
+	*fi = v
+	return nil
+}
+
+// Scan implements the sql.Scanner interface for BigInt
+func (fi *BigInt) Scan(src any) error {
+	return fi.scan(src, &defaultOptions)
+}
+
+func (fi *BigInt) scan(src any, opts *Options) error {
+	v, _, err := scan[BigInt](src, fi, opts)
+	if err != nil {
+		return err
+	}
+	*fi = v
+	return nil
+}
+
+// Value implements the driver.Valuer interface for BigInt,
+// the value is a decimal string
+func (fi BigInt) Value() (driver.Value, error) {
+	return fi.String(), nil
+}
+
+// UnmarshalXML method for BigInt,
+// empty elements and xsi:nil are the zero value
+func (fi *BigInt) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v, _, err := decodeXML[BigInt](d, start, fi)
+	if err != nil {
+		return err
+	}
+	*fi = v
+	return nil
+}
+
+// UnmarshalXMLAttr method for BigInt,
+// empty attributes are the zero value
+func (fi *BigInt) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, _, err := decodeXMLText[BigInt]([]byte(attr.Value), fi)
+	if err != nil {
+		return err
+	}
+	*fi = v
+	return nil
+}
+
+// MarshalXML method for BigInt
+func (fi BigInt) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXML(e, start, encodeText(fi), true)
+}
+
+// MarshalXMLAttr method for BigInt
+func (fi BigInt) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return encodeXMLAttr(name, encodeText(fi), true)
+}
+
+// Set implements the flag.Value interface for BigInt,
+// e.g. -id=18446744073709551616 or -id=0xff
+func (fi *BigInt) Set(s string) error {
+	return fi.UnmarshalText([]byte(s))
+}
+
+// NullBigInt can be used to decode any JSON value to BigInt,
+// with the same rules as BigInt
+type NullBigInt = Null[BigInt]
+
+// decodeBigInt from any JSON value.
+// Strings that are not valid numbers or hexadecimal will error
+func decodeBigInt(k Kind, bArr []byte, opts *Options) (v BigInt, err *CoercionError) {
+	// Value is a...
+	switch k {
+	case KindString:
+		if numK, number := textKind(bArr); numK == KindNumber {
+			return decodeBigNumber(k, number)
+		}
+		s := string(bArr)
+		sign, hex := "", s
+		if strings.HasPrefix(s, "-") {
+			sign, hex = "-", s[1:]
+		}
+		// The sign is before the prefix only, e.g. 0x-1 is not valid
+		digits, ok := cutHexPrefix(hex)
+		if ok && digits != "" && digits[0] != '-' && digits[0] != '+' {
+			if i, ok := new(big.Int).SetString(sign+digits, 16); ok {
+				return BigInt{i: i}, nil
+			}
+		}
+		return v, coercionError(k, ErrInvalidNumberString, nil)
+
+	case KindNumber:
+		return decodeBigNumber(k, bArr)
+
+	case KindBool:
+		if opts.BoolToNumber {
+			return BigInt{i: big.NewInt(boolToNumber[int64](bArr))}, nil
+		}
+		return v, coercionError(k, ErrBoolToNumber, nil)
+	}
+	return v, kindError(k)
+}
+
+// cutHexPrefix returns s without the 0x or 0X prefix,
+// and true if s has the prefix
+func cutHexPrefix(s string) (string, bool) {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		return s[2:], true
+	}
+	return s, false
+}
+
+// decodeBigNumber decodes the JSON number in bArr exactly,
+// numbers with a fraction will error
+func decodeBigNumber(k Kind, bArr []byte) (v BigInt, err *CoercionError) {
+	s := string(bArr)
+	if !strings.ContainsAny(s, ".eE") {
+		i, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return v, numberError(k, strconv.ErrSyntax)
+		}
+		return BigInt{i: i}, nil
+	}
+
+	d := Decimal{text: s}
+	if !exponentInRange(s) || d.Precision() > maxBigIntDigits {
+		return v, coercionError(k, ErrOutOfRange, nil)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return v, numberError(k, strconv.ErrSyntax)
+	}
+	if !r.IsInt() {
+		return v, coercionError(k, ErrFraction, nil)
+	}
+	return BigInt{i: new(big.Int).Set(r.Num())}, nil
+}
+
+// encodeBigInt as a JSON value, see Options.BigIntFormat
+func encodeBigInt(v BigInt, opts *Options) ([]byte, error) {
+	s := v.String()
+	switch opts.BigIntFormat {
+	case BigIntString:
+		return json.Marshal(s)
+	case BigIntSafe:
+		if v.i != nil && v.i.CmpAbs(maxSafeInteger) > 0 {
+			return json.Marshal(s)
+		}
+	}
+	return []byte(s), nil
+}
@@ -0,0 +1,147 @@
+package fuzzy_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBigInt(t *testing.T) {
+	type Data struct {
+		ID fuzzy.BigInt `json:"id"`
+	}
+	for _, tc := range []struct {
+		in  string
+		out string
+	}{
+		{`18446744073709551616`, "18446744073709551616"},
+		{`"18446744073709551616"`, "18446744073709551616"},
+		{`-18446744073709551616`, "-18446744073709551616"},
+		{`1.8e19`, "18000000000000000000"},
+		{`"1.8E19"`, "18000000000000000000"},
+		{`12.0`, "12"},
+		{`"0xFFFFFFFFFFFFFFFFFF"`, "4722366482869645213695"},
+		{`"-0x10"`, "-16"},
+		{`" 42 "`, "42"},
+		{`null`, "0"},
+	} {
+		d := Data{}
+		err := json.Unmarshal([]byte(`{"id": `+tc.in+`}`), &d)
+		require.NoError(t, err, tc.in)
+		require.Equal(t, tc.out, d.ID.String(), "value must match for %s", tc.in)
+
+		b, err := json.Marshal(d)
+		require.NoError(t, err)
+		require.Equal(t, `{"id":`+tc.out+`}`, string(b), "json must match")
+	}
+
+	for _, in := range []string{`1.5`, `"1.5"`, `1e-1`, `1e-1000`} {
+		d := Data{}
+		err := json.Unmarshal([]byte(`{"id": `+in+`}`), &d)
+		require.ErrorIs(t, err, fuzzy.ErrFraction, in)
+	}
+	for _, in := range []string{`"abc"`, `"0x"`, `"0xfg"`, `"1,000"`, `""`,
+		`"0x-1"`, `"-0x-1"`, `"0x+1"`, `"-0x+1"`, `"--0x1"`} {
+		d := Data{}
+		err := json.Unmarshal([]byte(`{"id": `+in+`}`), &d)
+		require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString, in)
+	}
+	d := Data{}
+	err := json.Unmarshal([]byte(`{"id": 1e1000000000}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrOutOfRange)
+	err = json.Unmarshal([]byte(`{"id": true}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrBoolToNumber)
+	err = json.Unmarshal([]byte(`{"id": {}}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrUnsupportedKind)
+
+	opts := fuzzy.Options{BoolToNumber: true}
+	err = fuzzy.UnmarshalWithOptions([]byte(`{"id": true}`), &d, opts)
+	require.NoError(t, err)
+	require.Equal(t, "1", d.ID.String(), "value must match")
+}
+
+func TestNullBigInt(t *testing.T) {
+	type Data struct {
+		ID fuzzy.NullBigInt `json:"id"`
+	}
+	d := Data{}
+	err := json.Unmarshal([]byte(`{"id": null}`), &d)
+	require.NoError(t, err)
+	require.False(t, d.ID.Valid, "id must not be valid")
+	b, err := json.Marshal(d)
+	require.NoError(t, err)
+	require.Equal(t, `{"id":null}`, string(b), "json must match")
+
+	err = json.Unmarshal([]byte(`{"id": "18446744073709551616"}`), &d)
+	require.NoError(t, err)
+	require.True(t, d.ID.Valid, "id must be valid")
+	want, _ := new(big.Int).SetString("18446744073709551616", 10)
+	require.Equal(t, want, d.ID.V.Int(), "value must match")
+}
+
+func TestBigIntFormat(t *testing.T) {
+	small := fuzzy.NewBigInt(big.NewInt(1<<53 - 1))
+	large := fuzzy.NewBigInt(big.NewInt(1 << 53))
+	for _, tc := range []struct {
+		format       fuzzy.BigIntStyle
+		small, large string
+	}{
+		{fuzzy.BigIntNumber, `9007199254740991`, `9007199254740992`},
+		{fuzzy.BigIntString, `"9007199254740991"`, `"9007199254740992"`},
+		{fuzzy.BigIntSafe, `9007199254740991`, `"9007199254740992"`},
+	} {
+		opts := fuzzy.Options{BigIntFormat: tc.format}
+		b, err := fuzzy.MarshalWithOptions(small, opts)
+		require.NoError(t, err)
+		require.Equal(t, tc.small, string(b), "json must match")
+		b, err = fuzzy.MarshalWithOptions(fuzzy.NullBigInt{V: large, Valid: true}, opts)
+		require.NoError(t, err)
+		require.Equal(t, tc.large, string(b), "json must match")
+
+		// Text is never quoted
+		text, err := large.MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, "9007199254740992", string(text), "text must match")
+	}
+
+	// The default format
+	b, err := json.Marshal(large)
+	require.NoError(t, err)
+	require.Equal(t, `9007199254740992`, string(b), "json must match")
+}
+
+func TestBigIntInterfaces(t *testing.T) {
+	x := big.NewInt(5)
+	fi := fuzzy.NewBigInt(x)
+	// The value is copied
+	x.SetInt64(6)
+	require.Equal(t, "5", fi.String(), "value must match")
+	fi.Int().SetInt64(7)
+	require.Equal(t, "5", fi.String(), "value must match")
+
+	require.NoError(t, fi.Scan([]byte("18446744073709551616")))
+	require.Equal(t, "18446744073709551616", fi.String(), "value must match")
+	v, err := fi.Value()
+	require.NoError(t, err)
+	require.Equal(t, "18446744073709551616", v, "value must match")
+
+	var ni fuzzy.NullBigInt
+	require.NoError(t, ni.Scan(nil))
+	require.False(t, ni.Valid, "id must not be valid")
+	require.NoError(t, ni.UnmarshalText([]byte("0x10")))
+	text, err := ni.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "16", string(text), "text must match")
+
+	require.NoError(t, fi.Set("0xff"))
+	require.Equal(t, "255", fi.String(), "flag must match")
+
+	fi, err = fuzzy.ParseBigInt("-12")
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(-12), fi.Int(), "value must match")
+	_, err = fuzzy.ParseBigInt("1.5")
+	require.ErrorIs(t, err, fuzzy.ErrFraction)
+}
@@ -0,0 +1,161 @@
+package fuzzy
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+)
+
+// goValue returns the kind and content of a Go value,
+// to be decoded with the same rules as JSON values, i.e.
+//   - nil and nil pointers are null, other pointers are dereferenced
+//   - strings and byte slices are strings, json.Number is a number
+//   - ints, uints and floats are numbers, bools are bools
+//   - driver.Valuer, e.g. the fuzzy types, are converted with Value
+//   - maps and structs are objects, slices and arrays are arrays
+//
+// Times are strings formatted with time.RFC3339Nano
+func goValue(src any) (k Kind, content []byte, err error) {
+	switch s := src.(type) {
+	case nil:
+		return KindNull, nil, nil
+	case []byte:
+		return KindString, s, nil
+	case string:
+		return KindString, []byte(s), nil
+	case json.Number:
+		k, content = textKind([]byte(s))
+		return k, content, nil
+	case int64:
+		return KindNumber, strconv.AppendInt(nil, s, 10), nil
+	case float64:
+		return KindNumber, strconv.AppendFloat(nil, s, 'f', -1, 64), nil
+	case bool:
+		return KindBool, strconv.AppendBool(nil, s), nil
+	case time.Time:
+		return KindString, s.AppendFormat(nil, time.RFC3339Nano), nil
+	}
+
+	v := reflect.ValueOf(src)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return KindNull, nil, nil
+		}
+		return goValue(v.Elem().Interface())
+	}
+	if valuer, ok := src.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return KindInvalid, nil, errors.WithStack(err)
+		}
+		return goValue(value)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return KindString, []byte(v.String()), nil
+	case reflect.Bool:
+		return KindBool, strconv.AppendBool(nil, v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return KindNumber, strconv.AppendInt(nil, v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return KindNumber, strconv.AppendUint(nil, v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return KindNumber,
+			strconv.AppendFloat(nil, v.Float(), 'f', -1, v.Type().Bits()), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return KindString, v.Bytes(), nil
+		}
+		return KindArray, nil, nil
+	case reflect.Array:
+		return KindArray, nil, nil
+	case reflect.Map, reflect.Struct:
+		return KindObject, nil, nil
+	}
+	return KindInvalid, nil, errors.Errorf("%T is not supported", src)
+}
+
+// to converts a Go value to T, see goValue.
+// Valid is false if the value is null
+func to[T Scalar](src any, dst any) (v T, valid bool, err error) {
+	if v, ok := timeValue[T](src); ok {
+		return v, true, nil
+	}
+	k, content, err := goValue(src)
+	if err != nil {
+		return v, false, withValue(
+			coercionError(k, ErrUnsupportedType, err), dst, nil)
+	}
+	return coerce[T](k, content, content, dst, &defaultOptions)
+}
+
+// ToString converts a Go value to a string,
+// with the same rules as String.UnmarshalJSON.
+// Null values are the empty string
+func ToString(src any) (string, error) {
+	v, _, err := to[string](src, (*string)(nil))
+	return v, err
+}
+
+// ToInt converts a Go value to an int64,
+// with the same rules as Int.UnmarshalJSON, e.g. "12" and 12.0 are 12.
+// Null values are zero
+func ToInt(src any) (int64, error) {
+	v, _, err := to[int64](src, (*int64)(nil))
+	return v, err
+}
+
+// ToFloat converts a Go value to a float64,
+// with the same rules as Float.UnmarshalJSON.
+// Null values are zero
+func ToFloat(src any) (float64, error) {
+	v, _, err := to[float64](src, (*float64)(nil))
+	return v, err
+}
+
+// ToBool converts a Go value to a bool,
+// with the same rules as Bool.UnmarshalJSON, e.g. "yes" and 1 are true.
+// Null values are false
+func ToBool(src any) (bool, error) {
+	v, _, err := to[bool](src, (*bool)(nil))
+	return v, err
+}
+
+// ToNullString converts a Go value to a NullString,
+// with the same rules as NullString.UnmarshalJSON.
+// The NullString is not valid if the value is null, or can't be converted
+func ToNullString(src any) NullString {
+	v, valid, err := to[string](src, (*NullString)(nil))
+	return NullString(null.NewString(v, valid && err == nil))
+}
+
+// ToNullInt converts a Go value to a NullInt,
+// with the same rules as NullInt.UnmarshalJSON.
+// The NullInt is not valid if the value is null, or can't be converted
+func ToNullInt(src any) NullInt {
+	v, valid, err := to[int64](src, (*NullInt)(nil))
+	return NullInt(null.NewInt(v, valid && err == nil))
+}
+
+// ToNullFloat converts a Go value to a NullFloat,
+// with the same rules as NullFloat.UnmarshalJSON.
+// The NullFloat is not valid if the value is null, or can't be converted
+func ToNullFloat(src any) NullFloat {
+	v, valid, err := to[float64](src, (*NullFloat)(nil))
+	return NullFloat(null.NewFloat(v, valid && err == nil))
+}
+
+// ToNullBool converts a Go value to a NullBool,
+// with the same rules as NullBool.UnmarshalJSON.
+// The NullBool is not valid if the value is null, or can't be converted
+func ToNullBool(src any) NullBool {
+	v, valid, err := to[bool](src, (*NullBool)(nil))
+	return NullBool(null.NewBool(v, valid && err == nil))
+}
@@ -0,0 +1,188 @@
+package fuzzy_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/mozey/fuzzy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// convertFixtures are decoded with UnmarshalJSON and the To functions,
+// the results must be the same
+var convertFixtures = []struct {
+	json string
+	// float is true if the value is the same decoded to float64
+	float bool
+}{
+	{`null`, true},
+	{`""`, true},
+	{`"abc"`, true},
+	{`" 12 "`, true},
+	{`"12"`, true},
+	{`"-1.5"`, true},
+	{`"1e3"`, true},
+	{`12`, true},
+	{`-1.5`, true},
+	{`12.0`, false},
+	{`1e3`, false},
+	{`0`, true},
+	{`1`, true},
+	{`2`, true},
+	{`9223372036854775807`, false},
+	{`9223372036854775808`, false},
+	{`true`, true},
+	{`false`, true},
+	{`"true"`, true},
+	{`"Yes"`, true},
+	{`"off"`, true},
+	{`"1"`, true},
+	{`{}`, true},
+	{`[]`, true},
+	{`[1]`, true},
+}
+
+// requireSameNull results for UnmarshalJSON and a ToNull function,
+// the value is not valid if UnmarshalJSON errors
+func requireSameNull(t *testing.T, fixture string, jsonV any, jsonErr error, v any, valid bool) {
+	t.Helper()
+	if jsonErr == nil {
+		require.Equal(t, jsonV, v, "value must match for %s", fixture)
+		return
+	}
+	require.False(t, valid, "value must not be valid for %s", fixture)
+}
+
+// requireSame results for UnmarshalJSON and a To function
+func requireSame(t *testing.T, fixture string, jsonV any, jsonErr error, v any, err error) {
+	t.Helper()
+	if jsonErr == nil {
+		require.NoError(t, err, fixture)
+		require.Equal(t, jsonV, v, "value must match for %s", fixture)
+		return
+	}
+	var jsonCErr, cErr *fuzzy.CoercionError
+	require.True(t, errors.As(jsonErr, &jsonCErr), fixture)
+	require.True(t, errors.As(err, &cErr), "error must be a CoercionError for %s", fixture)
+	require.ErrorIs(t, cErr, jsonCErr.Err, fixture)
+	require.Equal(t, jsonCErr.Kind, cErr.Kind, "kind must match for %s", fixture)
+}
+
+func TestToFixtures(t *testing.T) {
+	for _, fixture := range convertFixtures {
+		b := []byte(fixture.json)
+
+		// Numbers are json.Number, or float64
+		var number any
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		require.NoError(t, dec.Decode(&number))
+		srcs := []any{number}
+		if fixture.float {
+			var f any
+			require.NoError(t, json.Unmarshal(b, &f))
+			srcs = append(srcs, f)
+		}
+
+		for _, src := range srcs {
+			var s fuzzy.String
+			jsonErr := s.UnmarshalJSON(b)
+			v, err := fuzzy.ToString(src)
+			requireSame(t, fixture.json, string(s), jsonErr, v, err)
+
+			var i fuzzy.Int
+			jsonErr = i.UnmarshalJSON(b)
+			iv, err := fuzzy.ToInt(src)
+			requireSame(t, fixture.json, int64(i), jsonErr, iv, err)
+
+			var f fuzzy.Float
+			jsonErr = f.UnmarshalJSON(b)
+			fv, err := fuzzy.ToFloat(src)
+			requireSame(t, fixture.json, float64(f), jsonErr, fv, err)
+
+			var bo fuzzy.Bool
+			jsonErr = bo.UnmarshalJSON(b)
+			bv, err := fuzzy.ToBool(src)
+			requireSame(t, fixture.json, bool(bo), jsonErr, bv, err)
+
+			var ns fuzzy.NullString
+			jsonErr = ns.UnmarshalJSON(b)
+			nsv := fuzzy.ToNullString(src)
+			requireSameNull(t, fixture.json, ns, jsonErr, nsv, nsv.Valid)
+
+			var ni fuzzy.NullInt
+			jsonErr = ni.UnmarshalJSON(b)
+			niv := fuzzy.ToNullInt(src)
+			requireSameNull(t, fixture.json, ni, jsonErr, niv, niv.Valid)
+
+			var nf fuzzy.NullFloat
+			jsonErr = nf.UnmarshalJSON(b)
+			nfv := fuzzy.ToNullFloat(src)
+			requireSameNull(t, fixture.json, nf, jsonErr, nfv, nfv.Valid)
+
+			var nb fuzzy.NullBool
+			jsonErr = nb.UnmarshalJSON(b)
+			nbv := fuzzy.ToNullBool(src)
+			requireSameNull(t, fixture.json, nb, jsonErr, nbv, nbv.Valid)
+		}
+	}
+}
+
+func TestToGoValues(t *testing.T) {
+	type myInt int8
+	n := 7
+	var nilInt *int
+	for _, tc := range []struct {
+		src any
+		v   int64
+	}{
+		{int8(-8), -8},
+		{int16(16), 16},
+		{int32(32), 32},
+		{uint(1), 1},
+		{uint8(8), 8},
+		{uint64(64), 64},
+		{float32(2.5), 2},
+		{myInt(9), 9},
+		{[]byte("12"), 12},
+		{&n, 7},
+		{nilInt, 0},
+		{fuzzy.String("5"), 5},
+		{fuzzy.NullInt{}, 0},
+		{sql.NullInt64{Int64: 6, Valid: true}, 6},
+	} {
+		v, err := fuzzy.ToInt(tc.src)
+		require.NoError(t, err, "%T", tc.src)
+		require.Equal(t, tc.v, v, "value must match for %T", tc.src)
+	}
+
+	ni := fuzzy.ToNullInt(nilInt)
+	require.False(t, ni.Valid, "nil pointer must not be valid")
+	ni = fuzzy.ToNullInt(&n)
+	require.True(t, ni.Valid, "value must be valid")
+	require.Equal(t, int64(7), ni.Int64, "value must match")
+	ni = fuzzy.ToNullInt("abc")
+	require.False(t, ni.Valid, "invalid value must not be valid")
+
+	_, err := fuzzy.ToInt(uint64(1 << 63))
+	require.ErrorIs(t, err, fuzzy.ErrOutOfRange)
+
+	s, err := fuzzy.ToString(float32(0.1))
+	require.NoError(t, err)
+	require.Equal(t, "0.1", s, "value must match")
+
+	_, err = fuzzy.ToBool(make(chan int))
+	require.ErrorIs(t, err, fuzzy.ErrUnsupportedType)
+
+	_, err = fuzzy.ToInt(map[string]any{})
+	require.ErrorIs(t, err, fuzzy.ErrUnsupportedKind)
+
+	_, err = fuzzy.ToInt("abc")
+	var cErr *fuzzy.CoercionError
+	require.True(t, errors.As(err, &cErr))
+	require.Equal(t, "int64", cErr.Type, "type must match")
+	require.Equal(t, []byte("abc"), cErr.Value, "value must match")
+}
@@ -0,0 +1,259 @@
+package fuzzy
+
+import (
+	"encoding/csv"
+	"io"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// CSVDecoder reads CSV records and decodes them to structs,
+// the first record is the header. Columns are matched to fields by the
+// csv tag, or the field name, compared case-insensitively.
+// Columns without a field are skipped.
+// Cells that are valid numbers are decoded like JSON numbers, e.g. 2.5 for Int,
+// other cells like JSON strings.
+// Empty cells are null, i.e. not valid for the Null types
+type CSVDecoder struct {
+	r    *csv.Reader
+	opts Options
+	// header of the input, nil until it's read
+	header []string
+	// fields by column, for the type of the last call to Decode
+	typ    reflect.Type
+	fields []*field
+	// report of the last call to Decode
+	report Errors
+}
+
+// NewCSVDecoder returns a new decoder that reads from r,
+// r may be configured before the first call to Decode, e.g. r.Comma
+func NewCSVDecoder(r *csv.Reader) *CSVDecoder {
+	return &CSVDecoder{
+		r:    r,
+		opts: Options{EmptyString: EmptyNull},
+	}
+}
+
+// SetOptions replaces the options of the decoder,
+// the default options of a CSVDecoder have EmptyString set to EmptyNull
+func (dec *CSVDecoder) SetOptions(opts Options) {
+	dec.opts = opts
+}
+
+// Options returns the options of the decoder
+func (dec *CSVDecoder) Options() Options {
+	return dec.opts
+}
+
+// Report lists the cells the last call to Decode fell back on,
+// it's empty unless the options are Lenient
+func (dec *CSVDecoder) Report() Errors {
+	return dec.report
+}
+
+// Header returns the header, reading it if Decode has not been called
+func (dec *CSVDecoder) Header() ([]string, error) {
+	if dec.header != nil {
+		return dec.header, nil
+	}
+	header, err := dec.r.Read()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// The record may be reused by the reader
+	dec.header = append([]string(nil), header...)
+	return dec.header, nil
+}
+
+// Decode reads the next record and decodes it to the struct pointed to by v.
+// At the end of the input Decode returns io.EOF
+func (dec *CSVDecoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("fuzzy: CSVDecoder needs a pointer to a struct, not %T", v)
+	}
+	header, err := dec.Header()
+	if err != nil {
+		return err
+	}
+	if err = dec.mapFields(rv.Elem().Type(), header); err != nil {
+		return err
+	}
+
+	record, err := dec.r.Read()
+	if err == io.EOF {
+		return io.EOF
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	d := decodeState{opts: dec.opts, numbers: true}
+	err = dec.record(&d, record, rv.Elem())
+	dec.report = d.report
+	if err != nil {
+		return err
+	}
+	if len(d.errs) > 0 {
+		return d.errs
+	}
+	return nil
+}
+
+// DecodeAll reads all remaining records,
+// and appends them to dst, a pointer to a slice of structs
+func (dec *CSVDecoder) DecodeAll(dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return errors.Errorf("fuzzy: DecodeAll needs a pointer to a slice, not %T", dst)
+	}
+	slice := v.Elem()
+	for {
+		e := reflect.New(slice.Type().Elem())
+		err := dec.Decode(e.Interface())
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, e.Elem()))
+	}
+}
+
+// mapFields maps the columns of the header to the fields of struct type t
+func (dec *CSVDecoder) mapFields(t reflect.Type, header []string) error {
+	if dec.typ == t {
+		return nil
+	}
+	fields := cachedFields(t, "csv")
+	dec.fields = make([]*field, len(header))
+	for i, col := range header {
+		dec.fields[i] = lookupField(fields, col)
+		if dec.fields[i] == nil && dec.opts.DisallowUnknownFields {
+			return errors.Errorf("fuzzy: unknown column %q", col)
+		}
+	}
+	dec.typ = t
+	return nil
+}
+
+// record decodes the cells of record to the fields of struct v
+func (dec *CSVDecoder) record(d *decodeState, record []string, v reflect.Value) error {
+	for i, cell := range record {
+		if i >= len(dec.fields) || dec.fields[i] == nil {
+			continue
+		}
+		f := dec.fields[i]
+		fv, err := f.value(v)
+		if err != nil {
+			return err
+		}
+		errs, report := len(d.errs), len(d.report)
+		err = dec.cell(d, f, cell, fv)
+		line, _ := dec.r.FieldPos(i)
+		for _, cErr := range d.errs[errs:] {
+			cellError(cErr, dec.header[i], line)
+		}
+		for _, cErr := range d.report[report:] {
+			cellError(cErr, dec.header[i], line)
+		}
+		if err != nil {
+			var cErr *CoercionError
+			if errors.As(err, &cErr) {
+				cellError(cErr, dec.header[i], line)
+				return err
+			}
+			return errors.Wrapf(err,
+				"fuzzy: cannot decode column %s on line %d", dec.header[i], line)
+		}
+	}
+	return nil
+}
+
+// cell decodes a CSV cell to field f,
+// empty cells set pointers to nil if opts.EmptyString is EmptyNull
+func (dec *CSVDecoder) cell(d *decodeState, f *field, cell string, v reflect.Value) error {
+	restore, err := d.fieldOptions(f)
+	if err != nil {
+		return err
+	}
+	defer restore()
+	if cell == "" && d.opts.EmptyString == EmptyNull && v.Kind() == reflect.Pointer {
+		v.SetZero()
+		return nil
+	}
+	return d.form(&formNode{values: []string{cell}}, v)
+}
+
+// cellError sets the column and line on a CoercionError
+func cellError(err *CoercionError, col string, line int) {
+	unlocate(err)
+	err.Column = col
+	err.Line = line
+}
+
+// CSVEncoder writes structs as CSV records, the first record is the header.
+// Columns are named by the csv tag, or the field name.
+// Cells are formatted with the text forms of the fuzzy types,
+// invalid values are empty
+type CSVEncoder struct {
+	w *csv.Writer
+	// fields of the type of the first call to Encode
+	typ    reflect.Type
+	fields []field
+}
+
+// NewCSVEncoder returns a new encoder that writes to w,
+// call Flush after the last call to Encode
+func NewCSVEncoder(w *csv.Writer) *CSVEncoder {
+	return &CSVEncoder{w: w}
+}
+
+// Encode writes the struct v, or the struct pointed to by v, as a record.
+// The header is written before the first record
+func (enc *CSVEncoder) Encode(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return errors.Errorf("fuzzy: CSVEncoder needs a struct, not %T", v)
+	}
+
+	if enc.typ == nil {
+		enc.typ = rv.Type()
+		enc.fields = cachedFields(enc.typ, "csv")
+		header := make([]string, len(enc.fields))
+		for i, f := range enc.fields {
+			header[i] = f.name
+		}
+		if err := enc.w.Write(header); err != nil {
+			return errors.WithStack(err)
+		}
+	} else if rv.Type() != enc.typ {
+		return errors.Errorf("fuzzy: CSVEncoder needs a %v, not %v", enc.typ, rv.Type())
+	}
+
+	record := make([]string, len(enc.fields))
+	for i, f := range enc.fields {
+		fv, err := rv.FieldByIndexErr(f.index)
+		if err != nil {
+			// Nil embedded pointer
+			continue
+		}
+		if record[i], err = formatText(fv); err != nil {
+			return errors.Wrapf(err, "fuzzy: cannot encode column %s", f.name)
+		}
+	}
+	return errors.WithStack(enc.w.Write(record))
+}
+
+// Flush writes any buffered data,
+// and returns the error of the underlying writer if there is one
+func (enc *CSVEncoder) Flush() error {
+	enc.w.Flush()
+	return errors.WithStack(enc.w.Error())
+}
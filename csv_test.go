@@ -0,0 +1,150 @@
+package fuzzy_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+type Row struct {
+	SKU    fuzzy.String    `csv:"sku"`
+	Qty    fuzzy.Int       `csv:"qty" fuzzy:"round=half-up"`
+	Price  fuzzy.NullFloat `csv:"price"`
+	Active bool            `csv:"active" fuzzy:"true=Y,false=N"`
+	Note   fuzzy.NullString
+	Weight *float64 `csv:"weight"`
+}
+
+func TestCSVDecoder(t *testing.T) {
+	in := "SKU,qty,price,active,note,weight,extra\n" +
+		"12345,2.5,9.99,Y,a note,1.5,x\n" +
+		"A,1,,N,,,\n"
+	dec := fuzzy.NewCSVDecoder(csv.NewReader(strings.NewReader(in)))
+	rows := []Row{}
+	err := dec.DecodeAll(&rows)
+	require.NoError(t, err)
+	weight := 1.5
+	require.Equal(t, []Row{{
+		SKU:    "12345",
+		Qty:    3,
+		Price:  fuzzy.NullFloat(null.FloatFrom(9.99)),
+		Active: true,
+		Note:   fuzzy.NullString(null.StringFrom("a note")),
+		Weight: &weight,
+	}, {
+		SKU: "A",
+		Qty: 1,
+	}}, rows)
+
+	header, err := dec.Header()
+	require.NoError(t, err)
+	require.Equal(t, []string{"SKU", "qty", "price", "active", "note", "weight", "extra"}, header)
+
+	row := Row{}
+	require.Equal(t, io.EOF, dec.Decode(&row))
+}
+
+func TestCSVDecoderErrors(t *testing.T) {
+	in := "sku,qty,active\n" +
+		"A,1,Y\n" +
+		"B,x,Y\n"
+	dec := fuzzy.NewCSVDecoder(csv.NewReader(strings.NewReader(in)))
+	err := dec.DecodeAll(&[]Row{})
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	var cErr *fuzzy.CoercionError
+	require.ErrorAs(t, err, &cErr)
+	require.Equal(t, "qty", cErr.Column)
+	require.Equal(t, 3, cErr.Line)
+	require.Equal(t,
+		`fuzzy: cannot decode string x to fuzzy.Int in column qty on line 3: string is not a valid number: strconv.ParseInt: parsing "x": invalid syntax`,
+		err.Error())
+
+	// Collect errors
+	in = "sku,qty,active,price\n" +
+		"A,x,maybe,1\n"
+	dec = fuzzy.NewCSVDecoder(csv.NewReader(strings.NewReader(in)))
+	dec.SetOptions(fuzzy.Options{CollectErrors: true, EmptyString: fuzzy.EmptyNull})
+	row := Row{}
+	err = dec.Decode(&row)
+	var errs fuzzy.Errors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+	require.Equal(t, "qty", errs[0].Column)
+	require.Equal(t, "active", errs[1].Column)
+	require.Equal(t, 2, errs[1].Line)
+	require.Equal(t, fuzzy.NullFloat(null.FloatFrom(1)), row.Price)
+
+	// Lenient
+	dec = fuzzy.NewCSVDecoder(csv.NewReader(strings.NewReader(in)))
+	dec.SetOptions(fuzzy.Options{Lenient: true})
+	err = dec.Decode(&row)
+	require.NoError(t, err)
+	require.Len(t, dec.Report(), 2)
+	require.Equal(t, "active", dec.Report()[1].Column)
+
+	// Unknown columns
+	dec = fuzzy.NewCSVDecoder(csv.NewReader(strings.NewReader(in)))
+	dec.SetOptions(fuzzy.Options{DisallowUnknownFields: true})
+	err = dec.Decode(&struct {
+		SKU string `csv:"sku"`
+	}{})
+	require.EqualError(t, err, `fuzzy: unknown column "qty"`)
+
+	err = dec.Decode(Row{})
+	require.EqualError(t, err,
+		"fuzzy: CSVDecoder needs a pointer to a struct, not fuzzy_test.Row")
+}
+
+func TestCSVEncoder(t *testing.T) {
+	weight := 1.5
+	rows := []Row{{
+		SKU:    "a,b",
+		Qty:    2,
+		Price:  fuzzy.NullFloat(null.FloatFrom(1e21)),
+		Active: true,
+		Note:   fuzzy.NullString(null.StringFrom("n")),
+		Weight: &weight,
+	}, {
+		SKU: "c",
+	}}
+	buf := &bytes.Buffer{}
+	enc := fuzzy.NewCSVEncoder(csv.NewWriter(buf))
+	for _, row := range rows {
+		require.NoError(t, enc.Encode(row))
+	}
+	require.NoError(t, enc.Flush())
+	require.Equal(t, "sku,qty,price,active,Note,weight\n"+
+		"\"a,b\",2,1000000000000000000000,true,n,1.5\n"+
+		"c,0,,false,,\n", buf.String())
+
+	// Round trip
+	type Export struct {
+		ID     fuzzy.Int        `csv:"id"`
+		Price  fuzzy.NullFloat  `csv:"price"`
+		Active fuzzy.NullBool   `csv:"active"`
+		Note   fuzzy.NullString `csv:"note"`
+	}
+	exports := []Export{
+		{ID: 1, Price: fuzzy.NullFloat(null.FloatFrom(0.1)),
+			Active: fuzzy.NullBool(null.BoolFrom(false))},
+		{ID: 2, Note: fuzzy.NullString(null.StringFrom("a\nb"))},
+	}
+	buf.Reset()
+	enc = fuzzy.NewCSVEncoder(csv.NewWriter(buf))
+	for _, e := range exports {
+		require.NoError(t, enc.Encode(&e))
+	}
+	require.NoError(t, enc.Flush())
+	decoded := []Export{}
+	dec := fuzzy.NewCSVDecoder(csv.NewReader(buf))
+	require.NoError(t, dec.DecodeAll(&decoded))
+	require.Equal(t, exports, decoded)
+
+	require.EqualError(t, enc.Encode(1), "fuzzy: CSVEncoder needs a struct, not int")
+}
@@ -0,0 +1,261 @@
+package fuzzy
+
+import (
+	"database/sql/driver"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DateOrder of the day and month in ambiguous dates, e.g. 01/05/2024
+type DateOrder int
+
+const (
+	// DateDayFirst decodes 01/05/2024 as May 1
+	DateDayFirst DateOrder = iota
+	// DateMonthFirst decodes 01/05/2024 as January 5
+	DateMonthFirst
+)
+
+var dateOrderNames = [...]string{
+	DateDayFirst:   "dmy",
+	DateMonthFirst: "mdy",
+}
+
+// String method for DateOrder
+func (o DateOrder) String() string {
+	if o < 0 || int(o) >= len(dateOrderNames) {
+		return fmt.Sprintf("DateOrder(%d)", int(o))
+	}
+	return dateOrderNames[o]
+}
+
+// parseDateOrder is the inverse of DateOrder.String
+func parseDateOrder(s string) (DateOrder, error) {
+	for o, name := range dateOrderNames {
+		if s == name {
+			return DateOrder(o), nil
+		}
+	}
+	return DateDayFirst, errors.Errorf("invalid date order %q", s)
+}
+
+// dateLayouts are tried in order to decode strings to Date,
+// before the ambiguous layouts for the DateOrder
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"2006.01.02",
+	"20060102",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"Mon, Jan 2, 2006",
+	"Monday, January 2, 2006",
+	"2 Jan 2006",
+	"2 January 2006",
+	"02-Jan-2006",
+}
+
+// ambiguousDateLayouts by DateOrder
+var ambiguousDateLayouts = [...][]string{
+	DateDayFirst:   {"2/1/2006", "2-1-2006", "2.1.2006"},
+	DateMonthFirst: {"1/2/2006", "1-2-2006", "1.2.2006"},
+}
+
+// excelEpoch is day zero of spreadsheet serial dates,
+// serial 1 is January 1, 1900
+var excelEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// maxSerialDate is December 31, 9999
+const maxSerialDate = 2958465
+
+// Date is a civil date without a time or location,
+// it can be used to decode any JSON value to a date.
+// Strings are dates like 2024-05-01, 01/05/2024 or May 1, 2024,
+// or times with the layouts of Options.TimeLayouts, the time is dropped.
+// Ambiguous dates are decoded with Options.DateOrder.
+// Numbers, or strings that are valid numbers, are spreadsheet serial dates,
+// e.g. 45413 is 2024-05-01, the fraction is dropped.
+// Boolean values will error
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the date of t in its location
+func DateOf(t time.Time) Date {
+	var d Date
+	d.Year, d.Month, d.Day = t.Date()
+	return d
+}
+
+// String returns the date formatted as YYYY-MM-DD
+func (fd Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", fd.Year, fd.Month, fd.Day)
+}
+
+// IsZero returns true for the zero Date
+func (fd Date) IsZero() bool {
+	return fd == Date{}
+}
+
+// In returns the time at midnight of the date in loc
+func (fd Date) In(loc *time.Location) time.Time {
+	return time.Date(fd.Year, fd.Month, fd.Day, 0, 0, 0, 0, loc)
+}
+
+// MarshalJSON method for Date, formatted as YYYY-MM-DD
+func (fd Date) MarshalJSON() ([]byte, error) {
+	return encode(fd, &defaultOptions)
+}
+
+// UnmarshalJSON method for Date
+func (fd *Date) UnmarshalJSON(bArr []byte) (err error) {
+	return fd.unmarshalJSON(bArr, &defaultOptions)
+}
+
+func (fd *Date) unmarshalJSON(bArr []byte, opts *Options) (err error) {
+	v, _, err := decode[Date](bArr, fd, opts)
+	if err != nil {
+		return err
+	}
+	*fd = v
+	return
+}
+
+// MarshalText method for Date
+func (fd Date) MarshalText() ([]byte, error) {
+	return encodeText(fd), nil
+}
+
+// UnmarshalText method for Date
+func (fd *Date) UnmarshalText(text []byte) error {
+	return fd.unmarshalText(KindString, text, &defaultOptions)
+}
+
+func (fd *Date) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, _, err := decodeText[Date](k, text, fd, opts)
+	if err != nil {
+		return err
+	}
+	*fd = v
+	return nil
+}
+
+// Scan implements the sql.Scanner interface for Date
+func (fd *Date) Scan(src any) error {
+	return fd.scan(src, &defaultOptions)
+}
+
+func (fd *Date) scan(src any, opts *Options) error {
+	v, _, err := scan[Date](src, fd, opts)
+	if err != nil {
+		return err
+	}
+	*fd = v
+	return nil
+}
+
+// Value implements the driver.Valuer interface for Date,
+// the value is a string formatted as YYYY-MM-DD
+func (fd Date) Value() (driver.Value, error) {
+	return fd.String(), nil
+}
+
+// UnmarshalXML method for Date,
+// empty elements and xsi:nil are the zero value
+func (fd *Date) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v, _, err := decodeXML[Date](d, start, fd)
+	if err != nil {
+		return err
+	}
+	*fd = v
+	return nil
+}
+
+// UnmarshalXMLAttr method for Date,
+// empty attributes are the zero value
+func (fd *Date) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, _, err := decodeXMLText[Date]([]byte(attr.Value), fd)
+	if err != nil {
+		return err
+	}
+	*fd = v
+	return nil
+}
+
+// MarshalXML method for Date
+func (fd Date) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXML(e, start, encodeText(fd), true)
+}
+
+// MarshalXMLAttr method for Date
+func (fd Date) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return encodeXMLAttr(name, encodeText(fd), true)
+}
+
+// Set implements the flag.Value interface for Date,
+// e.g. -due=2024-05-01 or -due=01/05/2024
+func (fd *Date) Set(s string) error {
+	return fd.UnmarshalText([]byte(s))
+}
+
+// NullDate can be used to decode any JSON value to Date,
+// with the same rules as Date
+type NullDate = Null[Date]
+
+// decodeDate from any JSON value.
+// Strings that are not dates, times or numbers will error
+func decodeDate(k Kind, bArr []byte, opts *Options) (v Date, err *CoercionError) {
+	// Value is a...
+	switch k {
+	case KindString:
+		s := string(bArr)
+		for _, layouts := range [][]string{
+			dateLayouts, ambiguousDateLayouts[opts.dateOrder()],
+		} {
+			for _, layout := range layouts {
+				if t, parseErr := time.Parse(layout, s); parseErr == nil {
+					return DateOf(t), nil
+				}
+			}
+		}
+		if numK, number := textKind(bArr); numK == KindNumber {
+			return decodeSerialDate(k, number)
+		}
+		var parseErr error
+		for _, layout := range opts.timeLayouts() {
+			var t time.Time
+			if t, parseErr = time.Parse(layout, s); parseErr == nil {
+				return DateOf(t), nil
+			}
+		}
+		return v, coercionError(k, ErrInvalidDateString, parseErr)
+
+	case KindNumber:
+		return decodeSerialDate(k, bArr)
+	}
+	return v, kindError(k)
+}
+
+// decodeSerialDate decodes a spreadsheet serial date.
+// Serials before March 1, 1900 are adjusted for the leap day
+// spreadsheets count in 1900
+func decodeSerialDate(k Kind, bArr []byte) (v Date, err *CoercionError) {
+	f, err := decodeFloat(k, bArr, &defaultOptions)
+	if err != nil {
+		return v, err
+	}
+	days := math.Floor(f)
+	if days < 0 || days > maxSerialDate {
+		return v, coercionError(k, ErrOutOfRange, nil)
+	}
+	if days < 60 {
+		days++
+	}
+	return DateOf(excelEpoch.AddDate(0, 0, int(days))), nil
+}
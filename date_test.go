@@ -0,0 +1,152 @@
+package fuzzy_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDate(t *testing.T) {
+	type Data struct {
+		Date fuzzy.Date `json:"date"`
+	}
+	may1 := fuzzy.Date{Year: 2024, Month: time.May, Day: 1}
+	for _, tc := range []struct {
+		in  string
+		out fuzzy.Date
+	}{
+		{`"2024-05-01"`, may1},
+		{`"2024/05/01"`, may1},
+		{`"20240501"`, may1},
+		{`"01/05/2024"`, may1},
+		{`"1/5/2024"`, may1},
+		{`"01.05.2024"`, may1},
+		{`"May 1, 2024"`, may1},
+		{`"1 May 2024"`, may1},
+		{`"01-May-2024"`, may1},
+		// The time is dropped, the date is in the offset of the time
+		{`"2024-05-01T23:30:00-05:00"`, may1},
+		{`"2024-05-01 10:00:00"`, may1},
+		// Serial dates
+		{`45413`, may1},
+		{`45413.75`, may1},
+		{`"45413"`, may1},
+		{`1`, fuzzy.Date{Year: 1900, Month: time.January, Day: 1}},
+		{`59`, fuzzy.Date{Year: 1900, Month: time.February, Day: 28}},
+		{`61`, fuzzy.Date{Year: 1900, Month: time.March, Day: 1}},
+		{`null`, fuzzy.Date{}},
+	} {
+		d := Data{}
+		err := json.Unmarshal([]byte(`{"date": `+tc.in+`}`), &d)
+		require.NoError(t, err, tc.in)
+		require.Equal(t, tc.out, d.Date, "value must match for %s", tc.in)
+	}
+
+	d := Data{}
+	err := json.Unmarshal([]byte(`{"date": "someday"}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidDateString)
+	err = json.Unmarshal([]byte(`{"date": "31/02/2024"}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidDateString)
+	err = json.Unmarshal([]byte(`{"date": false}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrUnsupportedKind)
+	err = json.Unmarshal([]byte(`{"date": -1}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrOutOfRange)
+	err = json.Unmarshal([]byte(`{"date": 3000000}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrOutOfRange)
+
+	// Round trip
+	b, err := json.Marshal(Data{Date: may1})
+	require.NoError(t, err)
+	require.Equal(t, `{"date":"2024-05-01"}`, string(b), "json must match")
+	d = Data{}
+	require.NoError(t, json.Unmarshal(b, &d))
+	require.Equal(t, may1, d.Date, "value must match")
+
+	require.Equal(t, may1, fuzzy.DateOf(may1.In(time.UTC)), "value must match")
+	require.True(t, fuzzy.Date{}.IsZero(), "date must be zero")
+}
+
+func TestNullDate(t *testing.T) {
+	type Data struct {
+		Date fuzzy.NullDate `json:"date"`
+	}
+	d := Data{}
+	err := json.Unmarshal([]byte(`{"date": null}`), &d)
+	require.NoError(t, err)
+	require.False(t, d.Date.Valid, "date must not be valid")
+
+	err = json.Unmarshal([]byte(`{"date": "2024-05-01"}`), &d)
+	require.NoError(t, err)
+	require.True(t, d.Date.Valid, "date must be valid")
+
+	b, err := json.Marshal(d)
+	require.NoError(t, err)
+	require.Equal(t, `{"date":"2024-05-01"}`, string(b), "json must match")
+	b, err = json.Marshal(Data{})
+	require.NoError(t, err)
+	require.Equal(t, `{"date":null}`, string(b), "json must match")
+}
+
+func TestDateOrder(t *testing.T) {
+	type Data struct {
+		Date fuzzy.Date `json:"date"`
+	}
+	d := Data{}
+	opts := fuzzy.Options{DateOrder: fuzzy.DateMonthFirst}
+	err := fuzzy.UnmarshalWithOptions([]byte(`{"date": "01/05/2024"}`), &d, opts)
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.Date{Year: 2024, Month: time.January, Day: 5}, d.Date,
+		"value must match")
+
+	// Unambiguous dates are the same in any order
+	err = fuzzy.UnmarshalWithOptions([]byte(`{"date": "2024-05-01"}`), &d, opts)
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.Date{Year: 2024, Month: time.May, Day: 1}, d.Date,
+		"value must match")
+
+	type Tagged struct {
+		US fuzzy.NullDate `json:"us" fuzzy:"date=mdy"`
+		EU fuzzy.Date     `json:"eu" fuzzy:"date=dmy"`
+	}
+	tagged := Tagged{}
+	err = fuzzy.Unmarshal([]byte(`{"us": "12/31/2024", "eu": "31/12/2024"}`), &tagged)
+	require.NoError(t, err)
+	dec31 := fuzzy.Date{Year: 2024, Month: time.December, Day: 31}
+	require.Equal(t, dec31, tagged.US.V, "value must match")
+	require.Equal(t, dec31, tagged.EU, "value must match")
+
+	require.Equal(t, "mdy", fuzzy.DateMonthFirst.String())
+	require.Equal(t, "DateOrder(9)", fuzzy.DateOrder(9).String())
+}
+
+func TestDateInterfaces(t *testing.T) {
+	may1 := fuzzy.Date{Year: 2024, Month: time.May, Day: 1}
+
+	var fd fuzzy.Date
+	require.NoError(t, fd.Scan(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)))
+	require.Equal(t, may1, fd, "value must match")
+	require.NoError(t, fd.Scan([]byte("2024-05-01")))
+	require.Equal(t, may1, fd, "value must match")
+	v, err := fd.Value()
+	require.NoError(t, err)
+	require.Equal(t, "2024-05-01", v, "value must match")
+
+	var nd fuzzy.NullDate
+	require.NoError(t, nd.Scan(nil))
+	require.False(t, nd.Valid, "date must not be valid")
+	v, err = nd.Value()
+	require.NoError(t, err)
+	require.Nil(t, v, "value must be nil")
+
+	require.NoError(t, nd.UnmarshalText([]byte("45413")))
+	require.Equal(t, may1, nd.V, "value must match")
+	text, err := nd.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "2024-05-01", string(text), "text must match")
+
+	require.NoError(t, fd.Set("May 1, 2024"))
+	require.Equal(t, "2024-05-01", fd.String(), "flag must match")
+}
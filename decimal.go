@@ -0,0 +1,272 @@
+package fuzzy
+
+import (
+	"database/sql/driver"
+	"encoding/xml"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal can be used to decode any JSON number,
+// or string that is a valid JSON number, to an arbitrary-precision decimal.
+// The value is the coefficient times 10 to the power of an exponent
+// within ±10000, e.g. 1e-30, so it can always be converted to big.Rat.
+// The text of the number is kept as is, e.g. 12.50 or 1e-30,
+// and is never parsed as a float64, so it's encoded exactly as decoded.
+// Bools are 1 and 0 if Options.BoolToNumber is set, otherwise they error.
+// The zero value is 0
+type Decimal struct {
+	// text is a valid JSON number, empty for the zero value
+	text string
+}
+
+// maxDecimalExponent limits the exponent of decimals,
+// e.g. 1e2000000 is out of range
+const maxDecimalExponent = 10000
+
+// ParseDecimal parses s, a valid JSON number like -12.50 or 1e-30
+func ParseDecimal(s string) (Decimal, error) {
+	v, cErr := decodeDecimal(KindString, []byte(s), &defaultOptions)
+	if cErr != nil {
+		return v, withValue(cErr, (*Decimal)(nil), []byte(s))
+	}
+	return v, nil
+}
+
+// DecimalFromRat returns r rounded to scale digits after the point
+func DecimalFromRat(r *big.Rat, scale int) Decimal {
+	return Decimal{text: r.FloatString(scale)}
+}
+
+// String returns the exact text of the decimal
+func (fd Decimal) String() string {
+	if fd.text == "" {
+		return "0"
+	}
+	return fd.text
+}
+
+// IsZero returns true if the decimal is equal to zero, e.g. 0.00
+func (fd Decimal) IsZero() bool {
+	coef, _ := fd.parts()
+	return coef == ""
+}
+
+// Rat returns the exact value of the decimal
+func (fd Decimal) Rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(fd.String())
+	return r
+}
+
+// BigFloat returns the decimal rounded to prec bits of mantissa,
+// 64 if prec is zero
+func (fd Decimal) BigFloat(prec uint) *big.Float {
+	f, _, _ := big.ParseFloat(fd.String(), 10, prec, big.ToNearestEven)
+	return f
+}
+
+// Float64 returns the nearest float64, which may not be exact
+func (fd Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(fd.String(), 64)
+	return f
+}
+
+// Scale returns the number of digits after the point, e.g. 2 for 12.50,
+// and 30 for 1e-30
+func (fd Decimal) Scale() int {
+	_, exp := fd.parts()
+	return max(0, -exp)
+}
+
+// Precision returns the number of digits, e.g. 4 for 12.50 and 2 for 0.05,
+// like the precision of a SQL numeric type
+func (fd Decimal) Precision() int {
+	coef, exp := fd.parts()
+	return max(0, len(coef)+exp) + max(0, -exp)
+}
+
+// parts of the decimal, the value is the coefficient times 10^exp.
+// Leading zeros are removed from the coefficient, it's empty for zero.
+// The sign is ignored
+func (fd Decimal) parts() (coef string, exp int) {
+	s := strings.TrimPrefix(fd.String(), "-")
+	mantissa, e, ok := strings.Cut(strings.ToLower(s), "e")
+	if ok {
+		// The text is valid, but the exponent may be out of range
+		exp, _ = strconv.Atoi(e)
+	}
+	intPart, frac, _ := strings.Cut(mantissa, ".")
+	coef = strings.TrimLeft(intPart+frac, "0")
+	return coef, exp - len(frac)
+}
+
+// MarshalJSON method for Decimal, the exact text as a JSON number
+func (fd Decimal) MarshalJSON() ([]byte, error) {
+	return encode(fd, &defaultOptions)
+}
+
+// UnmarshalJSON method for Decimal
+func (fd *Decimal) UnmarshalJSON(bArr []byte) (err error) {
+	return fd.unmarshalJSON(bArr, &defaultOptions)
+}
+
+func (fd *Decimal) unmarshalJSON(bArr []byte, opts *Options) (err error) {
+	v, _, err := decode[Decimal](bArr, fd, opts)
+	if err != nil {
+		return err
+	}
+	*fd = v
+	return
+}
+
+// MarshalText method for Decimal
+func (fd Decimal) MarshalText() ([]byte, error) {
+	return encodeText(fd), nil
+}
+
+// UnmarshalText method for Decimal
+func (fd *Decimal) UnmarshalText(text []byte) error {
+	return fd.unmarshalText(KindString, text, &defaultOptions)
+}
+
+func (fd *Decimal) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, _, err := decodeText[Decimal](k, text, fd, opts)
+	if err != nil {
+		return err
+	}
+	*fd = v
+	return nil
+}
+
+// Scan implements the sql.Scanner interface for Decimal
+func (fd *Decimal) Scan(src any) error {
+	return fd.scan(src, &defaultOptions)
+}
+
+func (fd *Decimal) scan(src any, opts *Options) error {
+	v, _, err := scan[Decimal](src, fd, opts)
+	if err != nil {
+		return err
+	}
+	*fd = v
+	return nil
+}
+
+// Value implements the driver.Valuer interface for Decimal,
+// the value is the exact text
+func (fd Decimal) Value() (driver.Value, error) {
+	return fd.String(), nil
+}
+
+// UnmarshalXML method for Decimal,
+// empty elements and xsi:nil are the zero value
+func (fd *Decimal) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v, _, err := decodeXML[Decimal](d, start, fd)
+	if err != nil {
+		return err
+	}
+	*fd = v
+	return nil
+}
+
+// UnmarshalXMLAttr method for Decimal,
+// empty attributes are the zero value
+func (fd *Decimal) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, _, err := decodeXMLText[Decimal]([]byte(attr.Value), fd)
+	if err != nil {
+		return err
+	}
+	*fd = v
+	return nil
+}
+
+// MarshalXML method for Decimal
+func (fd Decimal) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXML(e, start, encodeText(fd), true)
+}
+
+// MarshalXMLAttr method for Decimal
+func (fd Decimal) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return encodeXMLAttr(name, encodeText(fd), true)
+}
+
+// Set implements the flag.Value interface for Decimal,
+// e.g. -amount=12.50
+func (fd *Decimal) Set(s string) error {
+	return fd.UnmarshalText([]byte(s))
+}
+
+// NullDecimal can be used to decode any JSON value to Decimal,
+// with the same rules as Decimal
+type NullDecimal = Null[Decimal]
+
+// decodeDecimal from any JSON value.
+// Strings that are not valid JSON numbers will error,
+// and the scale and precision are checked if they are limited in opts
+func decodeDecimal(k Kind, bArr []byte, opts *Options) (v Decimal, err *CoercionError) {
+	// Value is a...
+	switch k {
+	case KindString:
+		numK, number := textKind(bArr)
+		if numK != KindNumber {
+			return v, coercionError(k, ErrInvalidNumberString, nil)
+		}
+		v = Decimal{text: string(number)}
+
+	case KindNumber:
+		v = Decimal{text: string(bArr)}
+
+	case KindBool:
+		if !opts.BoolToNumber {
+			return v, coercionError(k, ErrBoolToNumber, nil)
+		}
+		v = Decimal{text: strconv.FormatInt(boolToNumber[int64](bArr), 10)}
+
+	default:
+		return v, kindError(k)
+	}
+	if !exponentInRange(v.text) {
+		return Decimal{}, coercionError(k, ErrOutOfRange, nil)
+	}
+	if _, exp := v.parts(); exp < -maxDecimalExponent || exp > maxDecimalExponent {
+		return Decimal{}, coercionError(k, ErrOutOfRange, nil)
+	}
+	if err = checkDecimal(k, v, opts); err != nil {
+		return Decimal{}, err
+	}
+	return v, nil
+}
+
+// checkDecimal returns an error if the scale or precision of v
+// is over the limit of opts, trailing zeros after the point are ignored
+func checkDecimal(k Kind, v Decimal, opts *Options) *CoercionError {
+	if opts.MaxScale == 0 && opts.MaxPrecision == 0 {
+		return nil
+	}
+	coef, exp := v.parts()
+	for exp < 0 && strings.HasSuffix(coef, "0") {
+		coef, exp = coef[:len(coef)-1], exp+1
+	}
+	trimmed := Decimal{text: coef + "e" + strconv.Itoa(exp)}
+	if coef == "" {
+		trimmed = Decimal{}
+	}
+	if opts.MaxScale > 0 && trimmed.Scale() > opts.MaxScale {
+		return coercionError(k, ErrScale, nil)
+	}
+	if opts.MaxPrecision > 0 && trimmed.Precision() > opts.MaxPrecision {
+		return coercionError(k, ErrPrecision, nil)
+	}
+	return nil
+}
+
+// exponentInRange returns true if the exponent of text fits in an int
+func exponentInRange(text string) bool {
+	_, e, ok := strings.Cut(strings.ToLower(text), "e")
+	if !ok {
+		return true
+	}
+	_, err := strconv.Atoi(e)
+	return err == nil
+}
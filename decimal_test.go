@@ -0,0 +1,163 @@
+package fuzzy_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimal(t *testing.T) {
+	type Data struct {
+		Decimal fuzzy.Decimal `json:"decimal"`
+	}
+	for _, tc := range []struct {
+		in  string
+		out string
+	}{
+		{`0.1`, "0.1"},
+		{`"0.1"`, "0.1"},
+		{`12345678901234567.89`, "12345678901234567.89"},
+		{`"12345678901234567.89"`, "12345678901234567.89"},
+		{`1e-30`, "1e-30"},
+		{`"1e-30"`, "1e-30"},
+		{`12.50`, "12.50"},
+		{`-0`, "-0"},
+		{`" 3 "`, "3"},
+		{`123456789012345678901234567890`, "123456789012345678901234567890"},
+		{`null`, "0"},
+	} {
+		d := Data{}
+		err := json.Unmarshal([]byte(`{"decimal": `+tc.in+`}`), &d)
+		require.NoError(t, err, tc.in)
+		require.Equal(t, tc.out, d.Decimal.String(), "value must match for %s", tc.in)
+
+		// Numbers round-trip exactly
+		b, err := json.Marshal(d)
+		require.NoError(t, err)
+		require.Equal(t, `{"decimal":`+tc.out+`}`, string(b), "json must match")
+	}
+
+	for _, in := range []string{`"abc"`, `"1,000"`, `"+1"`, `".5"`, `"0x10"`, `"NaN"`, `""`} {
+		d := Data{}
+		err := json.Unmarshal([]byte(`{"decimal": `+in+`}`), &d)
+		require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString, in)
+	}
+	d := Data{}
+	err := json.Unmarshal([]byte(`{"decimal": true}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrBoolToNumber)
+	err = json.Unmarshal([]byte(`{"decimal": []}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrUnsupportedKind)
+	err = json.Unmarshal([]byte(`{"decimal": 1e99999999999999999999}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrOutOfRange)
+	// Exponents are limited so decimals can be converted to big.Rat
+	for _, in := range []string{`1e2000000`, `"1e5000000"`, `1e-10001`} {
+		err = json.Unmarshal([]byte(`{"decimal": `+in+`}`), &d)
+		require.ErrorIs(t, err, fuzzy.ErrOutOfRange, in)
+	}
+	err = json.Unmarshal([]byte(`{"decimal": 1.5e10000}`), &d)
+	require.NoError(t, err)
+	require.NotNil(t, d.Decimal.Rat(), "rat must not be nil")
+
+	opts := fuzzy.Options{BoolToNumber: true}
+	err = fuzzy.UnmarshalWithOptions([]byte(`{"decimal": true}`), &d, opts)
+	require.NoError(t, err)
+	require.Equal(t, "1", d.Decimal.String(), "value must match")
+}
+
+func TestDecimalConversions(t *testing.T) {
+	d, err := fuzzy.ParseDecimal("12345678901234567.89")
+	require.NoError(t, err)
+	require.Equal(t, big.NewRat(123456789012345678_9, 100), d.Rat(), "rat must match")
+	require.Equal(t, "12345678901234567.89", d.BigFloat(128).Text('f', 2),
+		"float must match")
+	require.Equal(t, 12345678901234567.89, d.Float64(), "float64 must match")
+	require.Equal(t, 2, d.Scale(), "scale must match")
+	require.Equal(t, 19, d.Precision(), "precision must match")
+
+	d, err = fuzzy.ParseDecimal("0.1")
+	require.NoError(t, err)
+	// 0.1 + 0.2 is exact
+	sum := new(big.Rat).Add(d.Rat(), big.NewRat(2, 10))
+	require.Equal(t, "0.30", fuzzy.DecimalFromRat(sum, 2).String(), "sum must match")
+
+	for _, tc := range []struct {
+		in               string
+		scale, precision int
+	}{
+		{"12.50", 2, 4},
+		{"0.05", 2, 2},
+		{"1e3", 0, 4},
+		{"1.5e-3", 4, 4},
+		{"-123", 0, 3},
+		{"1e-30", 30, 30},
+	} {
+		d, err := fuzzy.ParseDecimal(tc.in)
+		require.NoError(t, err)
+		require.Equal(t, tc.scale, d.Scale(), "scale must match for %s", tc.in)
+		require.Equal(t, tc.precision, d.Precision(), "precision must match for %s", tc.in)
+	}
+
+	require.True(t, fuzzy.Decimal{}.IsZero(), "decimal must be zero")
+	d, err = fuzzy.ParseDecimal("-0.00")
+	require.NoError(t, err)
+	require.True(t, d.IsZero(), "decimal must be zero")
+
+	_, err = fuzzy.ParseDecimal("1.2.3")
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+}
+
+func TestDecimalScale(t *testing.T) {
+	type Data struct {
+		Amount fuzzy.NullDecimal `json:"amount" fuzzy:"scale=2,precision=6"`
+	}
+	for _, in := range []string{`1234.56`, `"1234.5600"`, `0.01`, `1e3`} {
+		d := Data{}
+		err := fuzzy.Unmarshal([]byte(`{"amount": `+in+`}`), &d)
+		require.NoError(t, err, in)
+		require.True(t, d.Amount.Valid, "amount must be valid")
+	}
+
+	d := Data{}
+	err := fuzzy.Unmarshal([]byte(`{"amount": 1.234}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrScale)
+	err = fuzzy.Unmarshal([]byte(`{"amount": "123456.7"}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrPrecision)
+	err = fuzzy.Unmarshal([]byte(`{"amount": 1e6}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrPrecision)
+
+	opts := fuzzy.Options{MaxScale: 1}
+	var fd fuzzy.Decimal
+	err = fuzzy.UnmarshalWithOptions([]byte(`0.05`), &fd, opts)
+	require.ErrorIs(t, err, fuzzy.ErrScale)
+
+	type Invalid struct {
+		Amount fuzzy.Decimal `json:"amount" fuzzy:"scale=x"`
+	}
+	err = fuzzy.Unmarshal([]byte(`{"amount": 1}`), &Invalid{})
+	require.ErrorContains(t, err, `invalid scale "x"`)
+}
+
+func TestDecimalInterfaces(t *testing.T) {
+	var fd fuzzy.Decimal
+	require.NoError(t, fd.Scan([]byte("12345678901234567.89")))
+	require.Equal(t, "12345678901234567.89", fd.String(), "value must match")
+	require.NoError(t, fd.Scan(int64(5)))
+	require.Equal(t, "5", fd.String(), "value must match")
+	v, err := fd.Value()
+	require.NoError(t, err)
+	require.Equal(t, "5", v, "value must match")
+
+	var nd fuzzy.NullDecimal
+	require.NoError(t, nd.Scan(nil))
+	require.False(t, nd.Valid, "decimal must not be valid")
+	require.NoError(t, nd.UnmarshalText([]byte("0.10")))
+	text, err := nd.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "0.10", string(text), "text must match")
+
+	require.NoError(t, fd.Set("12.50"))
+	require.Equal(t, "12.50", fd.String(), "flag must match")
+}
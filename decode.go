@@ -0,0 +1,625 @@
+package fuzzy
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Unmarshal is like json.Unmarshal,
+// but errors returned by fuzzy types are located in the input.
+// See CoercionError Path and Offset.
+// Plain Go strings, numbers and bools, as well as guregu/null and
+// database/sql null types, are decoded with the same rules as the fuzzy types,
+// e.g. an int64 field behaves like fuzzy.Int.
+// Struct fields are matched by their json tag like encoding/json
+func Unmarshal(data []byte, v any) error {
+	return UnmarshalWithOptions(data, v, Options{})
+}
+
+// UnmarshalWithOptions is like Unmarshal,
+// opts apply to all fuzzy types in v.
+// Use a Decoder to get the Report if opts is Lenient
+func UnmarshalWithOptions(data []byte, v any, opts Options) error {
+	if !json.Valid(data) {
+		// Let encoding/json report the syntax error
+		var raw json.RawMessage
+		return json.Unmarshal(data, &raw)
+	}
+	d := decodeState{data: data, opts: opts}
+	return d.unmarshal(v)
+}
+
+// Decoder reads and decodes JSON values from an input stream,
+// like json.Decoder, see Unmarshal
+type Decoder struct {
+	dec  *json.Decoder
+	opts Options
+	// report of the last call to Decode
+	report Errors
+}
+
+// NewDecoder returns a new decoder that reads from r
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{dec: json.NewDecoder(r)}
+}
+
+// SetOptions replaces the options of the decoder,
+// opts apply to all fuzzy types in values decoded after the call
+func (dec *Decoder) SetOptions(opts Options) {
+	dec.opts = opts
+}
+
+// Options returns the options of the decoder
+func (dec *Decoder) Options() Options {
+	return dec.opts
+}
+
+// DisallowUnknownFields causes the Decoder to return an error when the
+// destination is a struct and the input contains object keys which do not
+// match any non-ignored, exported fields in the destination
+func (dec *Decoder) DisallowUnknownFields() {
+	dec.opts.DisallowUnknownFields = true
+}
+
+// UseNumber causes the Decoder to unmarshal a number into an interface{}
+// as a json.Number instead of as a float64
+func (dec *Decoder) UseNumber() {
+	dec.opts.UseNumber = true
+}
+
+// CollectErrors causes the Decoder to continue past values that can't be
+// decoded to fuzzy types. Failed values are left as the zero value,
+// i.e. invalid for the Null types, and Decode returns Errors listing
+// all of them.
+// Other errors, e.g. syntax errors, still stop decoding
+func (dec *Decoder) CollectErrors() {
+	dec.opts.CollectErrors = true
+}
+
+// Lenient causes the Decoder to fall back to the zero value,
+// i.e. invalid for the Null types, for values that are not valid for the
+// type, e.g. strings that are not valid numbers and bools decoded to numbers.
+// Decode does not return an error for these,
+// instead they are recorded in the Report.
+// Objects and arrays decoded to fuzzy types, invalid JSON and unsupported
+// types are still errors
+func (dec *Decoder) Lenient() {
+	dec.opts.Lenient = true
+}
+
+// Report lists the values the last call to Decode fell back on,
+// it's empty unless the Decoder is Lenient
+func (dec *Decoder) Report() Errors {
+	return dec.report
+}
+
+// More reports whether there is another element in the
+// current array or object being parsed
+func (dec *Decoder) More() bool {
+	return dec.dec.More()
+}
+
+// InputOffset returns the input stream byte offset of the current decoder
+// position
+func (dec *Decoder) InputOffset() int64 {
+	return dec.dec.InputOffset()
+}
+
+// Decode reads the next JSON-encoded value from its input and stores it in
+// the value pointed to by v
+func (dec *Decoder) Decode(v any) error {
+	var raw json.RawMessage
+	if err := dec.dec.Decode(&raw); err != nil {
+		return err
+	}
+	d := decodeState{
+		data:   raw,
+		offset: dec.dec.InputOffset() - int64(len(raw)),
+		opts:   dec.opts,
+	}
+	err := d.unmarshal(v)
+	dec.report = d.report
+	return err
+}
+
+// decodeState walks a valid JSON value and the Go value it's decoded to,
+// keeping track of the location in the input
+type decodeState struct {
+	data []byte
+	// offset of data in the input
+	offset int64
+	// path is the list of JSON Pointer reference tokens of the current value
+	path []string
+	opts Options
+	// errs collected if opts.CollectErrors is set
+	errs Errors
+	// report of values that fell back to zero if opts.Lenient is set
+	report Errors
+	// checkbox sets missing bool fields to false, see DecodeValues
+	checkbox bool
+	// numbers in text are decoded like JSON numbers, see CSVDecoder
+	numbers bool
+}
+
+func (d *decodeState) unmarshal(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+	_, err := d.value(skipSpace(d.data, 0), rv)
+	if err != nil {
+		return err
+	}
+	if len(d.errs) > 0 {
+		return d.errs
+	}
+	return nil
+}
+
+// pointer returns the JSON Pointer of the current value, see RFC 6901
+func (d *decodeState) pointer() string {
+	var sb strings.Builder
+	for _, token := range d.path {
+		sb.WriteString("/")
+		sb.WriteString(escapePointer(token))
+	}
+	return sb.String()
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapePointer(token string) string {
+	return pointerEscaper.Replace(token)
+}
+
+// locate sets the path and offset on errors returned for the value at start
+func (d *decodeState) locate(err error, start int) error {
+	if err == nil {
+		return nil
+	}
+	var cErr *CoercionError
+	if errors.As(err, &cErr) && !cErr.located {
+		cErr.Path = d.pointer()
+		cErr.Offset = d.offset + int64(start)
+		cErr.located = true
+		return err
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		typeErr.Offset += d.offset + int64(start)
+	}
+	return err
+}
+
+// recoverError from coercion errors if opts.Lenient or opts.CollectErrors is set,
+// v is then set to the zero value and decoding continues
+func (d *decodeState) recoverError(err error, v reflect.Value) error {
+	if err == nil {
+		return nil
+	}
+	var cErr *CoercionError
+	if !errors.As(err, &cErr) {
+		return err
+	}
+	if d.opts.Lenient && lenient(cErr) {
+		v.SetZero()
+		d.report = append(d.report, cErr)
+		return nil
+	}
+	if d.opts.CollectErrors {
+		v.SetZero()
+		d.errs = append(d.errs, cErr)
+		return nil
+	}
+	return err
+}
+
+// lenient returns true for errors that may fall back to the zero value,
+// i.e. all coercion errors except for values of the wrong kind or type
+func lenient(err *CoercionError) bool {
+	return !errors.Is(err.Err, ErrUnsupportedKind) &&
+		!errors.Is(err.Err, ErrInvalidJSON) &&
+		!errors.Is(err.Err, ErrUnsupportedType)
+}
+
+// value decodes the JSON value at start to v,
+// end is the index after the value
+func (d *decodeState) value(start int, v reflect.Value) (end int, err error) {
+	end = valueEnd(d.data, start)
+	bArr := d.data[start:end]
+	k := kindOf(bArr)
+
+	v = indirect(v, k == KindNull)
+	if v.Kind() == reflect.Pointer {
+		// Only a settable pointer is returned for null
+		v.SetZero()
+		return end, nil
+	}
+
+	if !v.CanAddr() {
+		return end, d.fallback(start, v)
+	}
+
+	// Fuzzy types
+	if u, ok := v.Addr().Interface().(unmarshaler); ok {
+		err = d.locate(u.unmarshalJSON(bArr, &d.opts), start)
+		return end, d.recoverError(err, v)
+	}
+	if u, ok := fuzzyNull[unmarshaler](v); ok {
+		err = typed(u.unmarshalJSON(bArr, &d.opts), v.Type())
+		return end, d.recoverError(d.locate(err, start), v)
+	}
+
+	// Other types that decode themselves
+	if u, ok := v.Addr().Interface().(json.Unmarshaler); ok {
+		err = d.locate(u.UnmarshalJSON(bArr), start)
+		return end, d.recoverError(err, v)
+	}
+	if k == KindString && v.Addr().Type().Implements(textUnmarshalerType) {
+		return end, d.fallback(start, v)
+	}
+
+	// Plain types are decoded with the same rules as the fuzzy types
+	if isSQLNull(v.Type()) {
+		err = typed(d.sqlNull(bArr, v), v.Type())
+		return end, d.recoverError(d.locate(err, start), v)
+	}
+	if isScalar(v) {
+		_, err = d.scalar(bArr, v)
+		return end, d.recoverError(d.locate(err, start), v)
+	}
+
+	switch k {
+	case KindObject:
+		return end, d.object(start, v)
+	case KindArray:
+		return end, d.array(start, v)
+	}
+	return end, d.fallback(start, v)
+}
+
+// indirect walks down v allocating pointers as needed,
+// until it gets to a non-pointer or a pointer that implements
+// json.Unmarshaler. If null is true, indirect stops at the first settable
+// pointer, so it can be set to nil
+func indirect(v reflect.Value, null bool) reflect.Value {
+	for {
+		// Load value from interface,
+		// but only if the result will be usefully addressable
+		if v.Kind() == reflect.Interface && !v.IsNil() {
+			e := v.Elem()
+			if e.Kind() == reflect.Pointer && !e.IsNil() &&
+				(!null || e.Elem().Kind() == reflect.Pointer) {
+				v = e
+				continue
+			}
+		}
+
+		if v.Kind() != reflect.Pointer {
+			return v
+		}
+		if null && v.CanSet() {
+			return v
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if _, ok := v.Interface().(json.Unmarshaler); ok {
+			// The caller checks v.Elem().Addr()
+			return v.Elem()
+		}
+		v = v.Elem()
+	}
+}
+
+// object decodes the JSON object at start to a struct or map
+func (d *decodeState) object(start int, v reflect.Value) error {
+	var fields []field
+	switch v.Kind() {
+	case reflect.Struct:
+		fields = cachedFields(v.Type(), "json")
+	case reflect.Map:
+		if !validMapKey(v.Type().Key()) {
+			return d.fallback(start, v)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+	default:
+		return d.fallback(start, v)
+	}
+
+	i := start + 1
+	for {
+		i = skipSpace(d.data, i)
+		if d.data[i] == '}' {
+			return nil
+		}
+
+		// Key
+		keyEnd := valueEnd(d.data, i)
+		key, err := unquote(d.data[i:keyEnd])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		i = skipSpace(d.data, keyEnd) + 1 // colon
+		i = skipSpace(d.data, i)
+
+		// Value
+		d.path = append(d.path, key)
+		if v.Kind() == reflect.Struct {
+			i, err = d.field(i, v, fields, key)
+		} else {
+			i, err = d.mapIndex(i, v, key)
+		}
+		d.path = d.path[:len(d.path)-1]
+		if err != nil {
+			return err
+		}
+
+		i = skipSpace(d.data, i)
+		if d.data[i] == ',' {
+			i++
+		}
+	}
+}
+
+// field decodes the value at start to the struct field named key
+func (d *decodeState) field(
+	start int, v reflect.Value, fields []field, key string) (end int, err error) {
+
+	f := lookupField(fields, key)
+	if f == nil {
+		if d.opts.DisallowUnknownFields {
+			return start, errors.WithStack(fmt.Errorf("json: unknown field %q", key))
+		}
+		return valueEnd(d.data, start), nil
+	}
+	fv, err := f.value(v)
+	if err != nil {
+		return start, err
+	}
+	restore, err := d.fieldOptions(f)
+	if err != nil {
+		return start, err
+	}
+	defer restore()
+
+	if f.opts.Contains("string") {
+		end = valueEnd(d.data, start)
+		return end, d.quoted(start, fv)
+	}
+	return d.value(start, fv)
+}
+
+// fieldOptions applies the fuzzy tag of field f to d.opts,
+// call restore after the value of the field is decoded
+func (d *decodeState) fieldOptions(f *field) (restore func(), err error) {
+	opts, err := f.options(d.opts)
+	if err != nil {
+		return nil, err
+	}
+	// Field options apply to the value of the field only
+	prev := d.opts
+	d.opts = opts
+	return func() { d.opts = prev }, nil
+}
+
+// quoted decodes a value with the ",string" tag option.
+// Numbers and bools in strings are decoded by the fuzzy rules anyway,
+// strings are wrapped in another JSON string like encoding/json
+func (d *decodeState) quoted(start int, v reflect.Value) error {
+	bArr := d.data[start:valueEnd(d.data, start)]
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.String || !isScalar(v) || kindOf(bArr) != KindString {
+		_, err := d.value(start, v)
+		return err
+	}
+	inner, err := unquoteBytes(bArr)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = d.scalar(inner, v)
+	return d.recoverError(d.locate(err, start), v)
+}
+
+// mapIndex decodes the value at start to a new map element with key
+func (d *decodeState) mapIndex(
+	start int, v reflect.Value, key string) (end int, err error) {
+
+	t := v.Type()
+	kv, err := mapKey(t.Key(), key)
+	if err != nil {
+		return start, d.locate(err, start)
+	}
+	ev := reflect.New(t.Elem()).Elem()
+	end, err = d.value(start, ev)
+	if err != nil {
+		return end, err
+	}
+	v.SetMapIndex(kv, ev)
+	return end, nil
+}
+
+// validMapKey returns true if t can be decoded from an object key
+func validMapKey(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// mapKey converts an object key to the map key type t
+func mapKey(t reflect.Type, key string) (reflect.Value, error) {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		kv := reflect.New(t)
+		err := kv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key))
+		return kv.Elem(), err
+	}
+	kv := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		kv.SetString(key)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(key, 10, 64)
+		if err != nil || kv.OverflowInt(n) {
+			return kv, &json.UnmarshalTypeError{
+				Value: "number " + key, Type: t}
+		}
+		kv.SetInt(n)
+	default:
+		n, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || kv.OverflowUint(n) {
+			return kv, &json.UnmarshalTypeError{
+				Value: "number " + key, Type: t}
+		}
+		kv.SetUint(n)
+	}
+	return kv, nil
+}
+
+// array decodes the JSON array at start to a slice or array
+func (d *decodeState) array(start int, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+	default:
+		return d.fallback(start, v)
+	}
+
+	i := start + 1
+	n := 0
+	for {
+		i = skipSpace(d.data, i)
+		if d.data[i] == ']' {
+			break
+		}
+
+		if v.Kind() == reflect.Slice && n >= v.Len() {
+			if n >= v.Cap() {
+				v.Grow(1)
+			}
+			v.SetLen(n + 1)
+			v.Index(n).SetZero()
+		}
+
+		var err error
+		if n < v.Len() {
+			d.path = append(d.path, strconv.Itoa(n))
+			i, err = d.value(i, v.Index(n))
+			d.path = d.path[:len(d.path)-1]
+			if err != nil {
+				return err
+			}
+		} else {
+			// Ran out of fixed array, skip
+			i = valueEnd(d.data, i)
+		}
+		n++
+
+		i = skipSpace(d.data, i)
+		if d.data[i] == ',' {
+			i++
+		}
+	}
+
+	if v.Kind() == reflect.Array {
+		for ; n < v.Len(); n++ {
+			v.Index(n).SetZero()
+		}
+		return nil
+	}
+	if n == 0 && v.IsNil() {
+		v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+		return nil
+	}
+	v.SetLen(n)
+	return nil
+}
+
+// fallback leaves decoding of the value at start to encoding/json
+func (d *decodeState) fallback(start int, v reflect.Value) error {
+	bArr := d.data[start:valueEnd(d.data, start)]
+	if !v.CanAddr() {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(bArr))
+	if d.opts.UseNumber {
+		dec.UseNumber()
+	}
+	if d.opts.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	return d.locate(dec.Decode(v.Addr().Interface()), start)
+}
+
+// skipSpace returns the index of the first non-whitespace byte from i
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && isSpace(data[i]) {
+		i++
+	}
+	return i
+}
+
+// valueEnd returns the index after the valid JSON value starting at i
+func valueEnd(data []byte, i int) int {
+	if i >= len(data) {
+		return i
+	}
+	switch data[i] {
+	case '"':
+		for i++; i < len(data); i++ {
+			switch data[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+		return i
+
+	case '{', '[':
+		depth := 0
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case '"':
+				i = valueEnd(data, i) - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return i
+	}
+
+	// Number, bool or null
+	for ; i < len(data); i++ {
+		switch data[i] {
+		case ',', '}', ']', ' ', '\t', '\r', '\n':
+			return i
+		}
+	}
+	return i
+}
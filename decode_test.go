@@ -0,0 +1,349 @@
+package fuzzy_test
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mozey/fuzzy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type Order struct {
+	ID    fuzzy.Int       `json:"id"`
+	Qty   fuzzy.Int       `json:"qty"`
+	Price fuzzy.NullFloat `json:"price"`
+	Note  *fuzzy.String   `json:"note"`
+}
+
+type Orders struct {
+	Orders []Order `json:"orders"`
+}
+
+func TestUnmarshalLocation(t *testing.T) {
+	orders := make([]string, 18)
+	for i := range orders {
+		orders[i] = `{"id": 1, "qty": "2"}`
+	}
+	orders[17] = `{"id": 1, "qty": "abc"}`
+	b := []byte(`{"orders": [` + strings.Join(orders, ", ") + `]}`)
+
+	d := Orders{}
+	err := fuzzy.Unmarshal(b, &d)
+	require.Error(t, err)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	var cErr *fuzzy.CoercionError
+	require.True(t, errors.As(err, &cErr), "err must be a CoercionError")
+	require.Equal(t, "/orders/17/qty", cErr.Path)
+	require.Equal(t, int64(strings.LastIndex(string(b), `"abc"`)), cErr.Offset)
+	require.Equal(t, `"abc"`, string(b[cErr.Offset:cErr.Offset+5]))
+	require.Contains(t, err.Error(), `to fuzzy.Int at /orders/17/qty (offset `)
+
+	// json.Unmarshal does not locate the error
+	err = json.Unmarshal(b, &d)
+	require.True(t, errors.As(err, &cErr), "err must be a CoercionError")
+	require.Equal(t, "", cErr.Path)
+	require.NotContains(t, err.Error(), " at ")
+}
+
+func TestUnmarshalPointerEscape(t *testing.T) {
+	d := map[string]map[string]fuzzy.Int{}
+	err := fuzzy.Unmarshal([]byte(`{"a/b": {"m~n": true}}`), &d)
+	var cErr *fuzzy.CoercionError
+	require.True(t, errors.As(err, &cErr), "err must be a CoercionError")
+	require.Equal(t, "/a~1b/m~0n", cErr.Path)
+	require.ErrorIs(t, err, fuzzy.ErrBoolToNumber)
+
+	// Root
+	var i fuzzy.Int
+	err = fuzzy.Unmarshal([]byte(` true`), &i)
+	require.True(t, errors.As(err, &cErr), "err must be a CoercionError")
+	require.Equal(t, "", cErr.Path)
+	require.Equal(t, int64(1), cErr.Offset)
+	require.Contains(t, err.Error(), " at / (offset 1)")
+}
+
+func TestUnmarshalLikeJSON(t *testing.T) {
+	type Embedded struct {
+		Embedded string
+		Shadowed string `json:"shadowed"`
+	}
+	type Data struct {
+		Embedded
+		*Order
+		Shadowed string         `json:"shadowed"`
+		Plain    int            `json:"plain"`
+		Quoted   int64          `json:"quoted,string"`
+		Ignored  string         `json:"-"`
+		Any      any            `json:"any"`
+		Map      map[int]string `json:"map"`
+		Slice    []fuzzy.Bool   `json:"slice"`
+		Array    [2]fuzzy.Int   `json:"array"`
+		Ptr      **fuzzy.Float  `json:"ptr"`
+		Null     *fuzzy.Int     `json:"null"`
+		Raw      json.RawMessage
+		private  string
+	}
+	b := []byte(`{
+		"embedded": "a",
+		"shadowed": "b",
+		"id": 1,
+		"QTY": "2",
+		"note": 3,
+		"plain": 4,
+		"quoted": "5",
+		"Ignored": "c",
+		"any": {"a": [1, "b"]},
+		"map": {"1": "x", "2": "y"},
+		"slice": [1, "0", true],
+		"array": [1, 2, 3],
+		"ptr": "1.5",
+		"null": null,
+		"raw": [1, {}],
+		"private": "d",
+		"unknown": {"a": [1, 2]}
+	}`)
+
+	one := fuzzy.Int(1)
+	expected := Data{}
+	expected.Null = &one
+	require.NoError(t, json.Unmarshal(b, &expected))
+	actual := Data{}
+	actual.Null = &one
+	require.NoError(t, fuzzy.Unmarshal(b, &actual))
+	require.Equal(t, expected, actual)
+
+	require.Equal(t, "a", actual.Embedded.Embedded)
+	require.Equal(t, "", actual.Embedded.Shadowed)
+	require.Equal(t, "b", actual.Shadowed)
+	require.Equal(t, fuzzy.Int(2), actual.Qty)
+	require.Equal(t, fuzzy.String("3"), *actual.Note)
+	require.Equal(t, int64(5), actual.Quoted)
+	require.Equal(t, "", actual.Ignored)
+	require.Equal(t, [2]fuzzy.Int{1, 2}, actual.Array)
+	require.Equal(t, fuzzy.Float(1.5), **actual.Ptr)
+	require.Nil(t, actual.Null)
+
+	// Errors that are not returned by fuzzy types
+	b = []byte(`{"map": [1]}`)
+	err := json.Unmarshal(b, &expected)
+	var typeErr *json.UnmarshalTypeError
+	require.True(t, errors.As(err, &typeErr), "err must be a UnmarshalTypeError")
+	offset := typeErr.Offset
+	err = fuzzy.Unmarshal(b, &actual)
+	require.True(t, errors.As(err, &typeErr), "err must be a UnmarshalTypeError")
+	require.Equal(t, offset, typeErr.Offset)
+
+	err = fuzzy.Unmarshal([]byte(`{"plain": 1`), &actual)
+	var syntaxErr *json.SyntaxError
+	require.True(t, errors.As(err, &syntaxErr), "err must be a SyntaxError")
+
+	err = fuzzy.Unmarshal([]byte(`{}`), actual)
+	var invalidErr *json.InvalidUnmarshalError
+	require.True(t, errors.As(err, &invalidErr), "err must be a InvalidUnmarshalError")
+}
+
+func TestDecoder(t *testing.T) {
+	input := `{"qty": 1} {"qty": "2"}
+		{"qty": "x"} {"qty": 4, "foo": 1}`
+	dec := fuzzy.NewDecoder(strings.NewReader(input))
+
+	o := Order{}
+	require.NoError(t, dec.Decode(&o))
+	require.Equal(t, fuzzy.Int(1), o.Qty)
+	require.True(t, dec.More())
+	require.NoError(t, dec.Decode(&o))
+	require.Equal(t, fuzzy.Int(2), o.Qty)
+
+	// Offset is in the input stream
+	err := dec.Decode(&o)
+	var cErr *fuzzy.CoercionError
+	require.True(t, errors.As(err, &cErr), "err must be a CoercionError")
+	require.Equal(t, "/qty", cErr.Path)
+	require.Equal(t, int64(strings.Index(input, `"x"`)), cErr.Offset)
+
+	dec.DisallowUnknownFields()
+	err = dec.Decode(&o)
+	require.EqualError(t, err, `json: unknown field "foo"`)
+
+	err = dec.Decode(&o)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestDecoderUseNumber(t *testing.T) {
+	dec := fuzzy.NewDecoder(strings.NewReader(`{"a": 1.5}`))
+	dec.UseNumber()
+	var v map[string]any
+	require.NoError(t, dec.Decode(&v))
+	require.Equal(t, json.Number("1.5"), v["a"])
+}
+
+func TestDecoderCollectErrors(t *testing.T) {
+	b := []byte(`{"orders": [
+		{"id": 1, "qty": "abc", "price": true, "note": "a"},
+		{"id": true, "qty": 2, "price": "1.5", "note": [1]},
+		{"id": 3, "qty": 3, "price": 3}
+	]}`)
+
+	// The first error stops decoding
+	d := Orders{}
+	err := fuzzy.NewDecoder(strings.NewReader(string(b))).Decode(&d)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	var errs fuzzy.Errors
+	require.False(t, errors.As(err, &errs), "err must not be Errors")
+
+	// All errors are collected
+	d = Orders{}
+	dec := fuzzy.NewDecoder(strings.NewReader(string(b)))
+	dec.CollectErrors()
+	err = dec.Decode(&d)
+	require.Error(t, err)
+	require.True(t, errors.As(err, &errs), "err must be Errors")
+	require.Len(t, errs, 4)
+	paths := make([]string, len(errs))
+	for i, e := range errs {
+		paths[i] = e.Path
+	}
+	require.Equal(t, []string{
+		"/orders/0/qty", "/orders/0/price", "/orders/1/id", "/orders/1/note",
+	}, paths)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	require.ErrorIs(t, err, fuzzy.ErrBoolToNumber)
+	require.ErrorIs(t, err, fuzzy.ErrUnsupportedKind)
+	require.True(t, strings.HasPrefix(err.Error(), "fuzzy: 4 errors\n"))
+
+	// Failed values are zero or invalid, others are decoded
+	require.Len(t, d.Orders, 3)
+	require.Equal(t, fuzzy.Int(1), d.Orders[0].ID)
+	require.Equal(t, fuzzy.Int(0), d.Orders[0].Qty)
+	require.False(t, d.Orders[0].Price.Valid, "price must not be valid")
+	require.Equal(t, fuzzy.String("a"), *d.Orders[0].Note)
+	require.Equal(t, fuzzy.Int(0), d.Orders[1].ID)
+	require.Equal(t, fuzzy.Int(2), d.Orders[1].Qty)
+	require.Equal(t, 1.5, d.Orders[1].Price.Float64)
+	require.Equal(t, fuzzy.String(""), *d.Orders[1].Note)
+	require.Equal(t, fuzzy.Int(3), d.Orders[2].Qty)
+
+	// Errors other than coercion errors still stop decoding
+	dec = fuzzy.NewDecoder(strings.NewReader(`{"orders": {"qty": "x"}}`))
+	dec.CollectErrors()
+	err = dec.Decode(&d)
+	require.False(t, errors.As(err, &errs), "err must not be Errors")
+	var typeErr *json.UnmarshalTypeError
+	require.True(t, errors.As(err, &typeErr), "err must be a UnmarshalTypeError")
+}
+
+func TestDecoderLenient(t *testing.T) {
+	b := `{"orders": [
+		{"id": 1, "qty": "abc", "price": true},
+		{"id": "99999999999999999999", "qty": 2, "price": "1.5"}
+	]}`
+
+	// Strict
+	d := Orders{}
+	dec := fuzzy.NewDecoder(strings.NewReader(b))
+	err := dec.Decode(&d)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	require.Empty(t, dec.Report())
+
+	// Lenient
+	d = Orders{}
+	dec = fuzzy.NewDecoder(strings.NewReader(b + b))
+	dec.Lenient()
+	err = dec.Decode(&d)
+	require.NoError(t, err)
+	require.Len(t, d.Orders, 2)
+	require.Equal(t, fuzzy.Int(0), d.Orders[0].Qty)
+	require.False(t, d.Orders[0].Price.Valid, "price must not be valid")
+	require.Equal(t, fuzzy.Int(0), d.Orders[1].ID)
+	require.Equal(t, 1.5, d.Orders[1].Price.Float64)
+
+	report := dec.Report()
+	require.Len(t, report, 3)
+	require.Equal(t, "/orders/0/qty", report[0].Path)
+	require.ErrorIs(t, report[0], fuzzy.ErrInvalidNumberString)
+	require.Equal(t, "/orders/0/price", report[1].Path)
+	require.ErrorIs(t, report[1], fuzzy.ErrBoolToNumber)
+	require.Equal(t, "/orders/1/id", report[2].Path)
+	require.ErrorIs(t, report[2], fuzzy.ErrOutOfRange)
+
+	// Report is reset by Decode
+	require.NoError(t, dec.Decode(&d))
+	require.Len(t, dec.Report(), 3)
+	require.Greater(t, dec.Report()[0].Offset, report[0].Offset)
+
+	// Unsupported kinds are not recovered from
+	dec = fuzzy.NewDecoder(strings.NewReader(`{"qty": [1]}`))
+	dec.Lenient()
+	err = dec.Decode(&Order{})
+	require.ErrorIs(t, err, fuzzy.ErrUnsupportedKind)
+
+	// Unless errors are collected
+	dec = fuzzy.NewDecoder(strings.NewReader(`{"id": "x", "qty": [1]}`))
+	dec.Lenient()
+	dec.CollectErrors()
+	err = dec.Decode(&Order{})
+	var errs fuzzy.Errors
+	require.True(t, errors.As(err, &errs), "err must be Errors")
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, err, fuzzy.ErrUnsupportedKind)
+	require.Len(t, dec.Report(), 1)
+	require.ErrorIs(t, dec.Report()[0], fuzzy.ErrInvalidNumberString)
+}
+
+func TestDecoderLenientTypes(t *testing.T) {
+	for _, tc := range []struct {
+		json string
+		dst  any
+		err  error
+	}{
+		{`"garbage"`, &struct {
+			V fuzzy.NullTime `json:"v"`
+		}{}, fuzzy.ErrInvalidTimeString},
+		{`"garbage"`, &struct {
+			V fuzzy.Time `json:"v"`
+		}{}, fuzzy.ErrInvalidTimeString},
+		{`"garbage"`, &struct {
+			V fuzzy.NullDate `json:"v"`
+		}{}, fuzzy.ErrInvalidDateString},
+		{`"garbage"`, &struct {
+			V fuzzy.NullDuration `json:"v"`
+		}{}, fuzzy.ErrInvalidDurationString},
+		{`1.234`, &struct {
+			V fuzzy.NullDecimal `json:"v" fuzzy:"scale=2"`
+		}{}, fuzzy.ErrScale},
+		{`12345`, &struct {
+			V fuzzy.NullDecimal `json:"v" fuzzy:"precision=4"`
+		}{}, fuzzy.ErrPrecision},
+		{`"abc"`, &struct {
+			V fuzzy.Decimal `json:"v"`
+		}{}, fuzzy.ErrInvalidNumberString},
+		{`1.5`, &struct {
+			V fuzzy.NullBigInt `json:"v"`
+		}{}, fuzzy.ErrFraction},
+		{`"maybe"`, &struct {
+			V fuzzy.Null[bool] `json:"v" fuzzy:"true=y,false=n"`
+		}{}, fuzzy.ErrInvalidBoolString},
+	} {
+		b := `{"v": ` + tc.json + `}`
+		err := fuzzy.Unmarshal([]byte(b), tc.dst)
+		require.ErrorIs(t, err, tc.err, b)
+
+		dec := fuzzy.NewDecoder(strings.NewReader(b))
+		dec.Lenient()
+		require.NoError(t, dec.Decode(tc.dst), b)
+		require.Zero(t, reflect.ValueOf(tc.dst).Elem().Interface(),
+			"value must be zero for %s", b)
+		require.Len(t, dec.Report(), 1, b)
+		require.ErrorIs(t, dec.Report()[0], tc.err, b)
+		require.Equal(t, "/v", dec.Report()[0].Path, b)
+
+		// Values of the wrong kind are not recovered from
+		dec = fuzzy.NewDecoder(strings.NewReader(`{"v": [1]}`))
+		dec.Lenient()
+		require.ErrorIs(t, dec.Decode(tc.dst), fuzzy.ErrUnsupportedKind, b)
+	}
+}
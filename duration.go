@@ -0,0 +1,242 @@
+package fuzzy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DurationStyle is the format durations are encoded with
+type DurationStyle int
+
+const (
+	// DurationGo formats durations like time.Duration.String, e.g. "1m30s"
+	DurationGo DurationStyle = iota
+	// DurationISO8601 formats durations like "PT1M30S"
+	DurationISO8601
+	// DurationSeconds formats durations as a number of seconds, e.g. 90
+	DurationSeconds
+)
+
+// Duration can be used to decode any JSON value to time.Duration.
+// Strings are Go durations like "1m30s", or ISO 8601 durations like
+// "PT1M30S", years and months are not supported.
+// Numbers, or strings that are valid numbers,
+// are in the unit of Options.DurationUnit, seconds by default.
+// Boolean values will error
+type Duration = Value[time.Duration]
+
+// NullDuration can be used to decode any JSON value to time.Duration,
+// with the same rules as Duration
+type NullDuration = Null[time.Duration]
+
+// decodeDuration from any JSON value.
+// Strings that are valid numbers are in the unit, like numbers
+func decodeDuration(k Kind, bArr []byte, opts *Options) (
+	v time.Duration, err *CoercionError) {
+
+	// Value is a...
+	switch k {
+	case KindString:
+		if numK, number := textKind(bArr); numK == KindNumber {
+			v, parseErr := scaleDuration(string(number), opts.durationUnit())
+			if parseErr != nil {
+				return v, numberError(k, parseErr)
+			}
+			return v, nil
+		}
+		s := string(bArr)
+		var parseErr error
+		if isISODuration(s) {
+			v, parseErr = parseISODuration(s)
+		} else {
+			v, parseErr = time.ParseDuration(s)
+		}
+		if parseErr != nil {
+			if errors.Is(parseErr, strconv.ErrRange) {
+				return v, coercionError(k, ErrOutOfRange, parseErr)
+			}
+			return v, coercionError(k, ErrInvalidDurationString, parseErr)
+		}
+		return v, nil
+
+	case KindNumber:
+		v, parseErr := scaleDuration(string(bArr), opts.durationUnit())
+		if parseErr != nil {
+			return v, numberError(k, parseErr)
+		}
+		return v, nil
+	}
+	return v, kindError(k)
+}
+
+// parseDurationUnit parses a unit like ms or 1m,
+// the unit must be positive
+func parseDurationUnit(s string) (time.Duration, error) {
+	u, err := time.ParseDuration(s)
+	if err != nil {
+		u, err = time.ParseDuration("1" + s)
+	}
+	if err != nil || u <= 0 {
+		return 0, errors.Errorf("invalid duration unit %q", s)
+	}
+	return u, nil
+}
+
+// rangeError for a number s that is out of range
+func rangeError(s string) error {
+	return &strconv.NumError{Func: "ParseDuration", Num: s, Err: strconv.ErrRange}
+}
+
+// scaleDuration returns the number s times unit,
+// rounded to the nearest nanosecond
+func scaleDuration(s string, unit time.Duration) (time.Duration, error) {
+	// Integers are exact
+	if !strings.ContainsAny(s, ".eE") {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		d := i * int64(unit)
+		if unit != 0 && d/int64(unit) != i {
+			return 0, rangeError(s)
+		}
+		return time.Duration(d), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	d, ok := floatToInt64(math.Round(f * float64(unit)))
+	if !ok {
+		return 0, rangeError(s)
+	}
+	return time.Duration(d), nil
+}
+
+// isISODuration returns true if s starts like an ISO 8601 duration
+func isISODuration(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	return s != "" && (s[0] == 'P' || s[0] == 'p')
+}
+
+// parseISODuration parses an ISO 8601 duration, e.g. P1DT12H or -PT0.5S.
+// Weeks are 7 days and days are 24 hours,
+// years and months have no fixed duration and must be zero
+func parseISODuration(s string) (time.Duration, error) {
+	orig := s
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+	s = strings.ToUpper(s[1:])
+	if s == "" || s == "T" {
+		return 0, errors.Errorf("invalid ISO 8601 duration %q", orig)
+	}
+
+	var total time.Duration
+	designators := "YMWD"
+	units := []time.Duration{0, 0, 7 * 24 * time.Hour, 24 * time.Hour}
+	timePart := false
+	for s != "" {
+		if s[0] == 'T' {
+			if timePart {
+				return 0, errors.Errorf("invalid ISO 8601 duration %q", orig)
+			}
+			timePart = true
+			designators = "HMS"
+			units = []time.Duration{time.Hour, time.Minute, time.Second}
+			s = s[1:]
+			if s == "" {
+				return 0, errors.Errorf("invalid ISO 8601 duration %q", orig)
+			}
+			continue
+		}
+		end := strings.IndexFunc(s, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.' && r != ','
+		})
+		if end <= 0 {
+			return 0, errors.Errorf("invalid ISO 8601 duration %q", orig)
+		}
+		number := strings.ReplaceAll(s[:end], ",", ".")
+		i := strings.IndexByte(designators, s[end])
+		if i < 0 {
+			return 0, errors.Errorf("invalid ISO 8601 duration %q", orig)
+		}
+		d, err := scaleDuration(number, units[i])
+		if err != nil {
+			return 0, err
+		}
+		if units[i] == 0 {
+			if f, _ := strconv.ParseFloat(number, 64); f != 0 {
+				return 0, errors.Errorf(
+					"years and months are not supported in %q", orig)
+			}
+		}
+		if total > math.MaxInt64-d {
+			return 0, rangeError(orig)
+		}
+		total += d
+		// Designators must be in order
+		designators, units = designators[i+1:], units[i+1:]
+		s = s[end+1:]
+	}
+	if neg {
+		total = -total
+	}
+	return total, nil
+}
+
+// formatDuration formats d with Options.DurationFormat,
+// number is true for DurationSeconds
+func formatDuration(d time.Duration, opts *Options) (text []byte, number bool) {
+	switch opts.DurationFormat {
+	case DurationISO8601:
+		return []byte(formatISODuration(d)), false
+	case DurationSeconds:
+		return strconv.AppendFloat(nil, d.Seconds(), 'f', -1, 64), true
+	}
+	return []byte(d.String()), false
+}
+
+// formatISODuration formats d as an ISO 8601 duration with
+// hours, minutes and seconds, e.g. PT36H0.5S
+func formatISODuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	// Negative durations are formatted from the absolute value as uint64,
+	// math.MinInt64 has no positive int64
+	u := uint64(d)
+	if d < 0 {
+		b.WriteByte('-')
+		u = -u
+	}
+	b.WriteString("PT")
+	hours, u := u/uint64(time.Hour), u%uint64(time.Hour)
+	minutes, u := u/uint64(time.Minute), u%uint64(time.Minute)
+	if hours > 0 {
+		fmt.Fprintf(&b, "%dH", hours)
+	}
+	if minutes > 0 {
+		fmt.Fprintf(&b, "%dM", minutes)
+	}
+	if u > 0 {
+		b.WriteString(strconv.FormatFloat(float64(u)/1e9, 'f', -1, 64))
+		b.WriteByte('S')
+	}
+	return b.String()
+}
+
+// encodeDuration as a JSON value, see Options.DurationFormat
+func encodeDuration(d time.Duration, opts *Options) ([]byte, error) {
+	text, number := formatDuration(d, opts)
+	if number {
+		return text, nil
+	}
+	return json.Marshal(string(text))
+}
@@ -0,0 +1,165 @@
+package fuzzy_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuration(t *testing.T) {
+	type Data struct {
+		Duration fuzzy.Duration `json:"duration"`
+	}
+	for _, tc := range []struct {
+		in  string
+		out time.Duration
+	}{
+		{`"30s"`, 30 * time.Second},
+		{`"1h2m3.5s"`, time.Hour + 2*time.Minute + 3500*time.Millisecond},
+		{`"-1.5m"`, -90 * time.Second},
+		{`"PT30S"`, 30 * time.Second},
+		{`"pt0.5s"`, 500 * time.Millisecond},
+		{`"PT1H30M"`, 90 * time.Minute},
+		{`"P1DT12H"`, 36 * time.Hour},
+		{`"P1W"`, 7 * 24 * time.Hour},
+		{`"P0Y0M1D"`, 24 * time.Hour},
+		{`"PT0,25S"`, 250 * time.Millisecond},
+		{`"-PT1M"`, -time.Minute},
+		{`30`, 30 * time.Second},
+		{`30.5`, 30500 * time.Millisecond},
+		{`"30"`, 30 * time.Second},
+		{`-2`, -2 * time.Second},
+		{`1e-9`, time.Nanosecond},
+		{`null`, 0},
+	} {
+		d := Data{}
+		err := json.Unmarshal([]byte(`{"duration": `+tc.in+`}`), &d)
+		require.NoError(t, err, tc.in)
+		require.Equal(t, tc.out, d.Duration.V, "value must match for %s", tc.in)
+	}
+
+	for _, in := range []string{
+		`"soon"`, `"P"`, `"PT"`, `"P1M"`, `"P1Y"`, `"PT1S1M"`, `"PT1HT1M"`, `"P1H"`, `""`,
+	} {
+		d := Data{}
+		err := json.Unmarshal([]byte(`{"duration": `+in+`}`), &d)
+		require.ErrorIs(t, err, fuzzy.ErrInvalidDurationString, in)
+	}
+
+	d := Data{}
+	err := json.Unmarshal([]byte(`{"duration": true}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrUnsupportedKind)
+	err = json.Unmarshal([]byte(`{"duration": 1e12}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrOutOfRange)
+	err = json.Unmarshal([]byte(`{"duration": 9300000000}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrOutOfRange)
+	err = json.Unmarshal([]byte(`{"duration": "P200000D"}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrOutOfRange)
+}
+
+func TestNullDuration(t *testing.T) {
+	type Data struct {
+		Duration fuzzy.NullDuration `json:"duration"`
+	}
+	d := Data{}
+	err := json.Unmarshal([]byte(`{"duration": null}`), &d)
+	require.NoError(t, err)
+	require.False(t, d.Duration.Valid, "duration must not be valid")
+
+	err = json.Unmarshal([]byte(`{"duration": "PT1M30S"}`), &d)
+	require.NoError(t, err)
+	require.True(t, d.Duration.Valid, "duration must be valid")
+	require.Equal(t, 90*time.Second, d.Duration.V, "value must match")
+
+	b, err := json.Marshal(d)
+	require.NoError(t, err)
+	require.Equal(t, `{"duration":"1m30s"}`, string(b), "json must match")
+	b, err = json.Marshal(Data{})
+	require.NoError(t, err)
+	require.Equal(t, `{"duration":null}`, string(b), "json must match")
+}
+
+func TestDurationOptions(t *testing.T) {
+	type Data struct {
+		Duration fuzzy.Duration `json:"duration"`
+	}
+	d := Data{}
+	opts := fuzzy.Options{DurationUnit: time.Millisecond}
+	err := fuzzy.UnmarshalWithOptions([]byte(`{"duration": 1500}`), &d, opts)
+	require.NoError(t, err)
+	require.Equal(t, 1500*time.Millisecond, d.Duration.V, "value must match")
+
+	type Tagged struct {
+		Millis  fuzzy.Duration     `json:"millis" fuzzy:"unit=ms"`
+		Minutes fuzzy.NullDuration `json:"minutes" fuzzy:"unit=1m"`
+	}
+	tagged := Tagged{}
+	err = fuzzy.Unmarshal([]byte(`{"millis": "250", "minutes": 1.5}`), &tagged)
+	require.NoError(t, err)
+	require.Equal(t, 250*time.Millisecond, tagged.Millis.V, "value must match")
+	require.Equal(t, 90*time.Second, tagged.Minutes.V, "value must match")
+
+	type Invalid struct {
+		Duration fuzzy.Duration `json:"duration" fuzzy:"unit=fortnight"`
+	}
+	err = fuzzy.Unmarshal([]byte(`{"duration": 1}`), &Invalid{})
+	require.ErrorContains(t, err, `invalid duration unit "fortnight"`)
+}
+
+func TestDurationFormat(t *testing.T) {
+	for _, tc := range []struct {
+		format fuzzy.DurationStyle
+		d      time.Duration
+		out    string
+	}{
+		{fuzzy.DurationGo, 90 * time.Second, `"1m30s"`},
+		{fuzzy.DurationISO8601, 90 * time.Second, `"PT1M30S"`},
+		{fuzzy.DurationISO8601, 36*time.Hour + 500*time.Millisecond, `"PT36H0.5S"`},
+		{fuzzy.DurationISO8601, -time.Hour, `"-PT1H"`},
+		{fuzzy.DurationISO8601, 0, `"PT0S"`},
+		{fuzzy.DurationSeconds, 90 * time.Second, `90`},
+		{fuzzy.DurationSeconds, 1500 * time.Millisecond, `1.5`},
+	} {
+		opts := fuzzy.Options{DurationFormat: tc.format}
+		b, err := fuzzy.MarshalWithOptions(fuzzy.Duration{V: tc.d}, opts)
+		require.NoError(t, err)
+		require.Equal(t, tc.out, string(b), "json must match")
+
+		// Round trip
+		var d fuzzy.Duration
+		require.NoError(t, json.Unmarshal(b, &d))
+		require.Equal(t, tc.d, d.V, "value must match for %s", b)
+	}
+
+	// The default format
+	b, err := json.Marshal(fuzzy.NullDuration{V: time.Minute, Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, `"1m0s"`, string(b), "json must match")
+}
+
+func TestDurationInterfaces(t *testing.T) {
+	var fd fuzzy.Duration
+	require.NoError(t, fd.Scan(int64(30)))
+	require.Equal(t, 30*time.Second, fd.V, "value must match")
+	require.NoError(t, fd.Scan([]byte("PT1M")))
+	require.Equal(t, time.Minute, fd.V, "value must match")
+	v, err := fd.Value()
+	require.NoError(t, err)
+	require.Equal(t, "1m0s", v, "value must match")
+
+	v, err = fuzzy.Null[time.Duration]{V: time.Second, Valid: true}.Value()
+	require.NoError(t, err)
+	require.Equal(t, "1s", v, "value must match")
+
+	var nd fuzzy.NullDuration
+	require.NoError(t, nd.Scan(nil))
+	require.False(t, nd.Valid, "duration must not be valid")
+
+	require.NoError(t, nd.Set("PT5S"))
+	require.Equal(t, "5s", nd.String(), "flag must match")
+	require.NoError(t, fd.UnmarshalText([]byte("2.5")))
+	require.Equal(t, 2500*time.Millisecond, fd.V, "value must match")
+}
@@ -0,0 +1,294 @@
+package fuzzy
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// MarshalWithOptions is like json.Marshal,
+// the formats in opts apply to all fuzzy types in v,
+// e.g. Options.TimeFormat. Values without fuzzy types are encoded by
+// encoding/json, struct fields are named by their json tag
+func MarshalWithOptions(v any, opts Options) ([]byte, error) {
+	e := encodeState{opts: opts}
+	return e.value(reflect.ValueOf(v))
+}
+
+// Encoder writes JSON values to an output stream,
+// like json.Encoder, see MarshalWithOptions
+type Encoder struct {
+	w      io.Writer
+	opts   Options
+	prefix string
+	indent string
+}
+
+// NewEncoder returns a new encoder that writes to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// SetOptions replaces the options of the encoder,
+// opts apply to all fuzzy types in values encoded after the call
+func (enc *Encoder) SetOptions(opts Options) {
+	enc.opts = opts
+}
+
+// Options returns the options of the encoder
+func (enc *Encoder) Options() Options {
+	return enc.opts
+}
+
+// SetIndent indents each value like json.Encoder.SetIndent
+func (enc *Encoder) SetIndent(prefix, indent string) {
+	enc.prefix = prefix
+	enc.indent = indent
+}
+
+// Encode writes the JSON encoding of v followed by a newline
+func (enc *Encoder) Encode(v any) error {
+	b, err := MarshalWithOptions(v, enc.opts)
+	if err != nil {
+		return err
+	}
+	if enc.prefix != "" || enc.indent != "" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, b, enc.prefix, enc.indent); err != nil {
+			return errors.WithStack(err)
+		}
+		b = buf.Bytes()
+	}
+	_, err = enc.w.Write(append(b, '\n'))
+	return errors.WithStack(err)
+}
+
+// encodeState walks a Go value and encodes it as JSON,
+// fuzzy types are encoded with the options
+type encodeState struct {
+	opts Options
+}
+
+var (
+	marshalerType     = reflect.TypeFor[marshaler]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+)
+
+func (e *encodeState) value(v reflect.Value) ([]byte, error) {
+	if !v.IsValid() {
+		return []byte(`null`), nil
+	}
+	if !hasFuzzyTypes(v.Type()) {
+		return marshal(v)
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return []byte(`null`), nil
+		}
+	}
+	if v.Type().Implements(marshalerType) {
+		return v.Interface().(marshaler).marshalJSON(&e.opts)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return e.value(v.Elem())
+	case reflect.Struct:
+		return e.object(v)
+	case reflect.Map:
+		return e.mapValue(v)
+	case reflect.Slice, reflect.Array:
+		return e.array(v)
+	}
+	return marshal(v)
+}
+
+// marshal v with encoding/json,
+// addressable values may use MarshalJSON methods with pointer receivers
+func marshal(v reflect.Value) ([]byte, error) {
+	if v.CanAddr() {
+		v = v.Addr()
+	}
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return b, nil
+}
+
+// object encodes the struct v with the fields named by the json tag,
+// and the omitempty, omitzero and string options of encoding/json
+func (e *encodeState) object(v reflect.Value) ([]byte, error) {
+	b := []byte{'{'}
+	for _, f := range cachedFields(v.Type(), "json") {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// Nil embedded pointer
+			continue
+		}
+		if f.opts.Contains("omitempty") && isEmptyValue(fv) ||
+			f.opts.Contains("omitzero") && isZeroValue(fv) {
+			continue
+		}
+		value, err := e.value(fv)
+		if err != nil {
+			return nil, err
+		}
+		if f.opts.Contains("string") && quotable(fv.Type()) && string(value) != "null" {
+			// Like encoding/json, the value is encoded again as a string
+			if value, err = json.Marshal(string(value)); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		if len(b) > 1 {
+			b = append(b, ',')
+		}
+		name, err := json.Marshal(f.name)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		b = append(append(append(b, name...), ':'), value...)
+	}
+	return append(b, '}'), nil
+}
+
+// mapValue encodes the map v, keys are encoded and sorted by encoding/json
+func (e *encodeState) mapValue(v reflect.Value) ([]byte, error) {
+	m := reflect.MakeMapWithSize(reflect.MapOf(v.Type().Key(), rawMessageType), v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		value, err := e.value(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		m.SetMapIndex(iter.Key(), reflect.ValueOf(json.RawMessage(value)))
+	}
+	return marshal(m)
+}
+
+// array encodes the slice or array v
+func (e *encodeState) array(v reflect.Value) ([]byte, error) {
+	b := []byte{'['}
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		value, err := e.value(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, value...)
+	}
+	return append(b, ']'), nil
+}
+
+var fuzzyTypesCache sync.Map // map[reflect.Type]bool
+
+// hasFuzzyTypes is true if values of type t may contain fuzzy types,
+// types with their own MarshalJSON or MarshalText methods are left to
+// encoding/json
+func hasFuzzyTypes(t reflect.Type) bool {
+	if b, ok := fuzzyTypesCache.Load(t); ok {
+		return b.(bool)
+	}
+	b := containsFuzzyTypes(t, map[reflect.Type]bool{})
+	fuzzyTypesCache.Store(t, b)
+	return b
+}
+
+func containsFuzzyTypes(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if t.Implements(marshalerType) {
+		return true
+	}
+	if hasMarshalMethods(t) {
+		return false
+	}
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	switch t.Kind() {
+	case reflect.Interface:
+		// The dynamic type is checked when the value is encoded
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return containsFuzzyTypes(t.Elem(), visited)
+	case reflect.Struct:
+		for _, f := range cachedFields(t, "json") {
+			if containsFuzzyTypes(f.typ, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isEmptyValue is like the omitempty check of encoding/json
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// isZeroValue is like the omitzero check of encoding/json,
+// the IsZero method is used if the type has one
+func isZeroValue(v reflect.Value) bool {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return true
+	}
+	if v.CanAddr() {
+		v = v.Addr()
+	}
+	if z, ok := v.Interface().(interface{ IsZero() bool }); ok {
+		return z.IsZero()
+	}
+	return reflect.Indirect(v).IsZero()
+}
+
+// hasMarshalMethods is true if t or *t has a MarshalJSON or MarshalText
+// method, encoding/json then uses the method
+func hasMarshalMethods(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	return t.Implements(jsonMarshalerType) || pt.Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || pt.Implements(textMarshalerType)
+}
+
+// quotable is true if the string tag option applies to type t,
+// i.e. strings, numbers and bools, or pointers to them.
+// Types with MarshalJSON or MarshalText methods are not quoted,
+// e.g. Int, like encoding/json
+func quotable(t reflect.Type) bool {
+	if hasMarshalMethods(t) {
+		return false
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		if hasMarshalMethods(t) {
+			return false
+		}
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8,
+		reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package fuzzy_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+// Stamp has its own MarshalJSON, the fields are not walked
+type Stamp struct {
+	At fuzzy.Time
+}
+
+func (s Stamp) MarshalJSON() ([]byte, error) {
+	return []byte(`"stamp"`), nil
+}
+
+type Audit struct {
+	By string `json:"by"`
+}
+
+type Event struct {
+	Audit
+	Name    string                    `json:"name"`
+	At      fuzzy.Time                `json:"at"`
+	Ends    *fuzzy.NullTime           `json:"ends,omitempty"`
+	Count   int                       `json:"count,string"`
+	Empty   string                    `json:"empty,omitempty"`
+	Zero    fuzzy.Decimal             `json:"zero,omitzero"`
+	Times   []fuzzy.Time              `json:"times"`
+	ByName  map[string]fuzzy.NullTime `json:"by_name"`
+	Any     any                       `json:"any"`
+	Stamp   Stamp                     `json:"stamp"`
+	Skip    fuzzy.Time                `json:"-"`
+	private fuzzy.Time
+}
+
+func TestMarshalWithOptions(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	e := Event{
+		Audit:  Audit{By: "me"},
+		Name:   "<a>",
+		At:     fuzzy.Time{V: ts},
+		Count:  3,
+		Times:  []fuzzy.Time{{V: ts}},
+		ByName: map[string]fuzzy.NullTime{"b": {}, "a": {V: ts, Valid: true}},
+		Any:    fuzzy.Time{V: ts},
+		Stamp:  Stamp{At: fuzzy.Time{V: ts}},
+	}
+
+	// The default options encode like encoding/json
+	want, err := json.Marshal(e)
+	require.NoError(t, err)
+	b, err := fuzzy.MarshalWithOptions(e, fuzzy.Options{})
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(b), "json must match")
+	b, err = fuzzy.MarshalWithOptions(&e, fuzzy.Options{})
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(b), "json must match")
+
+	opts := fuzzy.Options{TimeFormat: fuzzy.TimeFormatUnix}
+	b, err = fuzzy.MarshalWithOptions(e, opts)
+	require.NoError(t, err)
+	require.Equal(t, `{"by":"me","name":"\u003ca\u003e","at":1714557600,`+
+		`"count":"3","times":[1714557600],"by_name":{"a":1714557600,"b":null},`+
+		`"any":1714557600,"stamp":"stamp"}`, string(b), "json must match")
+
+	e.Ends = &fuzzy.NullTime{V: ts, Valid: true}
+	e.Times = nil
+	e.Any = nil
+	b, err = fuzzy.MarshalWithOptions(e, opts)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"ends":1714557600,`, "json must match")
+	require.Contains(t, string(b), `"times":null,`, "json must match")
+	require.Contains(t, string(b), `"any":null,`, "json must match")
+
+	// Values without fuzzy types
+	b, err = fuzzy.MarshalWithOptions([]int{1, 2}, opts)
+	require.NoError(t, err)
+	require.Equal(t, `[1,2]`, string(b), "json must match")
+	b, err = fuzzy.MarshalWithOptions(nil, opts)
+	require.NoError(t, err)
+	require.Equal(t, `null`, string(b), "json must match")
+	_, err = fuzzy.MarshalWithOptions(map[string]any{"c": make(chan int)}, opts)
+	require.Error(t, err)
+}
+
+func TestMarshalStringOption(t *testing.T) {
+	type Tagged struct {
+		N  fuzzy.Int       `json:"n,string"`
+		P  *fuzzy.Int      `json:"p,string"`
+		NF fuzzy.NullFloat `json:"nf,string"`
+		I  int             `json:"i,string"`
+		At fuzzy.Time      `json:"at,string"`
+	}
+	n := fuzzy.Int(6)
+	v := Tagged{N: 5, P: &n, I: 7}
+
+	// Types with MarshalJSON are not quoted, like encoding/json
+	want, err := json.Marshal(v)
+	require.NoError(t, err)
+	b, err := fuzzy.MarshalWithOptions(v, fuzzy.Options{})
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(b), "json must match")
+	require.Contains(t, string(b), `"n":5,"p":6,"nf":null,"i":"7"`, "json must match")
+}
+
+func TestEncoder(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	enc := fuzzy.NewEncoder(&buf)
+	enc.SetOptions(fuzzy.Options{TimeFormat: time.DateOnly})
+	require.Equal(t, time.DateOnly, enc.Options().TimeFormat, "options must match")
+
+	require.NoError(t, enc.Encode(map[string]fuzzy.Time{"at": {V: ts}}))
+	enc.SetIndent("", "  ")
+	require.NoError(t, enc.Encode([]fuzzy.Time{{V: ts}}))
+	require.Equal(t, "{\"at\":\"2024-05-01\"}\n[\n  \"2024-05-01\"\n]\n",
+		buf.String(), "json must match")
+}
@@ -0,0 +1,135 @@
+package fuzzy
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DecodeEnv decodes environment variables to the struct pointed to by v,
+// see DecodeEnvMap
+func DecodeEnv(v any, prefix string) error {
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		env[key] = value
+	}
+	return DecodeEnvMap(env, v, prefix)
+}
+
+// DecodeEnvMap decodes the variables in env to the struct pointed to by v.
+// Variables are matched to fields by the env tag, or the upper case field name,
+// following the prefix. Fields of nested structs are prefixed by the name of
+// the struct field and an underscore, e.g.
+//
+//	type Config struct {
+//		Port    int      `env:"PORT" default:"8080"`
+//		Verbose bool     `env:"VERBOSE"`
+//		Hosts   []string `env:"HOSTS,required"`
+//		DB      struct {
+//			URL string `env:"URL"`
+//		} `env:"DB"`
+//	}
+//	err := fuzzy.DecodeEnvMap(env, &cfg, "APP_")
+//
+// reads APP_PORT, APP_VERBOSE, APP_HOSTS and APP_DB_URL.
+// Values are decoded with the same rules as JSON strings,
+// and slices from comma separated values.
+// Durations like 5s are decoded to time.Duration fields.
+// The default tag is used if a variable is not set,
+// variables that are set to the empty string are decoded as is.
+// A required variable that is not set is an error with reason ErrRequired
+func DecodeEnvMap(env map[string]string, v any, prefix string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("fuzzy: DecodeEnv needs a pointer to a struct, not %T", v)
+	}
+	d := decodeState{}
+	return d.env(env, prefix, rv.Elem())
+}
+
+// env decodes the variables in env to the fields of struct v
+func (d *decodeState) env(env map[string]string, prefix string, v reflect.Value) error {
+	for _, f := range cachedFields(v.Type(), "env") {
+		name := f.name
+		if !f.tagged {
+			name = strings.ToUpper(name)
+		}
+		name = prefix + name
+		fv, err := f.value(v)
+		if err != nil {
+			return err
+		}
+		if err := d.envField(env, name, f, fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envField decodes the variable name to field f
+func (d *decodeState) envField(
+	env map[string]string, name string, f field, v reflect.Value) error {
+
+	restore, err := d.fieldOptions(&f)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	// Nested structs
+	t := f.typ
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && !isText(reflect.New(t).Elem()) {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		return d.env(env, name+"_", v)
+	}
+
+	s, ok := env[name]
+	if !ok {
+		s, ok = f.tag.Lookup("default")
+	}
+	if !ok {
+		if f.opts.Contains("required") {
+			err := withValue(coercionError(KindNull, ErrRequired, nil),
+				reflect.New(f.typ).Interface(), nil)
+			return variableError(err, name)
+		}
+		return nil
+	}
+
+	values := []string{s}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		values = nil
+		if s != "" {
+			values = strings.Split(s, ",")
+		}
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+	}
+	return variableError(d.form(&formNode{values: values}, v), name)
+}
+
+// variableError sets the variable on a CoercionError,
+// other errors are wrapped with the variable name
+func variableError(err error, name string) error {
+	if err == nil {
+		return nil
+	}
+	var cErr *CoercionError
+	if errors.As(unlocate(err), &cErr) {
+		cErr.Variable = name
+		return err
+	}
+	return errors.Wrapf(err, "fuzzy: cannot decode variable %s", name)
+}
@@ -0,0 +1,131 @@
+package fuzzy_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+type Config struct {
+	Port    fuzzy.Int      `env:"PORT" default:"8080"`
+	Debug   fuzzy.Bool     `env:"DEBUG"`
+	Verbose bool           `env:"VERBOSE"`
+	Quiet   bool           `env:"QUIET"`
+	Ratio   float64        `env:"RATIO" default:"0.5"`
+	Hosts   []string       `env:"HOSTS,required"`
+	Ports   []fuzzy.Int    `env:"PORTS"`
+	Timeout fuzzy.NullInt  `env:"TIMEOUT" fuzzy:"nullempty"`
+	Name    *fuzzy.String  // Matched by name
+	Limit   null.Int       `env:"LIMIT"`
+	DB      DBConfig       `env:"DB"`
+	Cache   *DBConfig      `env:"CACHE"`
+	Ignored fuzzy.String   `env:"-"`
+	Level   fuzzy.NullBool `env:"LEVEL"`
+}
+
+type DBConfig struct {
+	URL      string    `env:"URL" default:"postgres://localhost"`
+	MaxConns fuzzy.Int `env:"MAX_CONNS"`
+}
+
+func TestDecodeEnv(t *testing.T) {
+	env := map[string]string{
+		"APP_DEBUG":           "yes",
+		"APP_VERBOSE":         "1",
+		"APP_QUIET":           "",
+		"APP_HOSTS":           "a, b,c",
+		"APP_PORTS":           "1, 2",
+		"APP_TIMEOUT":         "",
+		"APP_NAME":            "svc",
+		"APP_LIMIT":           "10",
+		"APP_DB_MAX_CONNS":    "5",
+		"APP_CACHE_URL":       "redis://localhost",
+		"APP_CACHE_MAX_CONNS": "2",
+		"APP_IGNORED":         "x",
+		"PORT":                "1",
+	}
+	cfg := Config{Quiet: true}
+	err := fuzzy.DecodeEnvMap(env, &cfg, "APP_")
+	require.NoError(t, err)
+	name := fuzzy.String("svc")
+	require.Equal(t, Config{
+		Port:    8080,
+		Debug:   true,
+		Verbose: true,
+		Quiet:   false,
+		Ratio:   0.5,
+		Hosts:   []string{"a", "b", "c"},
+		Ports:   []fuzzy.Int{1, 2},
+		Name:    &name,
+		Limit:   null.IntFrom(10),
+		DB:      DBConfig{URL: "postgres://localhost", MaxConns: 5},
+		Cache:   &DBConfig{URL: "redis://localhost", MaxConns: 2},
+	}, cfg)
+
+	// Real environment
+	t.Setenv("TEST_HOSTS", "x")
+	t.Setenv("TEST_PORT", "1e3")
+	cfg = Config{}
+	err = fuzzy.DecodeEnv(&cfg, "TEST_")
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	t.Setenv("TEST_PORT", "1000")
+	err = fuzzy.DecodeEnv(&cfg, "TEST_")
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.Int(1000), cfg.Port)
+	require.Equal(t, []string{"x"}, cfg.Hosts)
+}
+
+func TestDecodeEnvDuration(t *testing.T) {
+	var cfg struct {
+		Timeout time.Duration `env:"TIMEOUT"`
+		Retry   time.Duration `env:"RETRY" default:"PT1M"`
+		Wait    time.Duration `env:"WAIT"`
+	}
+	t.Setenv("TEST_TIMEOUT", "5s")
+	t.Setenv("TEST_WAIT", "1000")
+	err := fuzzy.DecodeEnv(&cfg, "TEST_")
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, cfg.Timeout, "value must match")
+	require.Equal(t, time.Minute, cfg.Retry, "value must match")
+	// Numbers are nanoseconds like time.Duration
+	require.Equal(t, time.Microsecond, cfg.Wait, "value must match")
+
+	t.Setenv("TEST_TIMEOUT", "soon")
+	err = fuzzy.DecodeEnv(&cfg, "TEST_")
+	require.ErrorIs(t, err, fuzzy.ErrInvalidDurationString)
+	var cErr *fuzzy.CoercionError
+	require.ErrorAs(t, err, &cErr)
+	require.Equal(t, "TEST_TIMEOUT", cErr.Variable)
+}
+
+func TestDecodeEnvErrors(t *testing.T) {
+	err := fuzzy.DecodeEnvMap(map[string]string{}, &Config{}, "")
+	require.ErrorIs(t, err, fuzzy.ErrRequired)
+	require.EqualError(t, err,
+		"fuzzy: cannot decode null to []string in variable HOSTS: required value is missing")
+
+	err = fuzzy.DecodeEnvMap(map[string]string{
+		"HOSTS": "a", "PORTS": "1,x",
+	}, &Config{}, "")
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	var cErr *fuzzy.CoercionError
+	require.ErrorAs(t, err, &cErr)
+	require.Equal(t, "PORTS", cErr.Variable)
+	require.Equal(t,
+		`fuzzy: cannot decode string x to fuzzy.Int in variable PORTS: string is not a valid number: strconv.ParseInt: parsing "x": invalid syntax`,
+		err.Error())
+
+	err = fuzzy.DecodeEnvMap(map[string]string{
+		"HOSTS": "a", "LIMIT": "x",
+	}, &Config{}, "")
+	require.ErrorAs(t, err, &cErr)
+	require.Equal(t, "null.Int", cErr.Type)
+	require.Equal(t, "LIMIT", cErr.Variable)
+
+	err = fuzzy.DecodeEnvMap(map[string]string{}, Config{}, "")
+	require.EqualError(t, err,
+		"fuzzy: DecodeEnv needs a pointer to a struct, not fuzzy_test.Config")
+}
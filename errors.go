@@ -0,0 +1,245 @@
+package fuzzy
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrBoolToNumber is the reason a JSON bool can't be decoded to a number
+	ErrBoolToNumber = errors.New("bool can't be decoded to a number")
+	// ErrInvalidNumberString is the reason a JSON string
+	// can't be decoded to a number
+	ErrInvalidNumberString = errors.New("string is not a valid number")
+	// ErrFraction is the reason a number with a fraction can't be decoded
+	// to Int, if Options.Rounding is RoundReject
+	ErrFraction = errors.New("number has a fraction")
+	// ErrInvalidBoolString is the reason a JSON string can't be decoded
+	// to Bool, if it's not in Options.BoolTrue or Options.BoolFalse
+	ErrInvalidBoolString = errors.New("string is not a valid bool")
+	// ErrInvalidTimeString is the reason a JSON string can't be decoded
+	// to Time with any of the layouts of Options.TimeLayouts
+	ErrInvalidTimeString = errors.New("string is not a valid time")
+	// ErrInvalidDateString is the reason a JSON string can't be decoded
+	// to Date
+	ErrInvalidDateString = errors.New("string is not a valid date")
+	// ErrInvalidDurationString is the reason a JSON string can't be decoded
+	// to Duration
+	ErrInvalidDurationString = errors.New("string is not a valid duration")
+	// ErrScale is the reason a number can't be decoded to Decimal,
+	// if it has more digits after the point than Options.MaxScale
+	ErrScale = errors.New("number has too many digits after the point")
+	// ErrPrecision is the reason a number can't be decoded to Decimal,
+	// if it has more digits than Options.MaxPrecision
+	ErrPrecision = errors.New("number has too many digits")
+	// ErrOutOfRange is the reason a number is too big for the target type
+	ErrOutOfRange = errors.New("number is out of range")
+	// ErrUnsupportedKind is the reason JSON objects and arrays
+	// can't be decoded to a fuzzy type
+	ErrUnsupportedKind = errors.New("unsupported kind")
+	// ErrUnsupportedType is the reason a Go value, e.g. a database/sql
+	// driver value, can't be decoded to a fuzzy type
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrRequired is the reason for a required value that is missing,
+	// e.g. an environment variable
+	ErrRequired = errors.New("required value is missing")
+	// ErrInvalidJSON is the reason malformed input can't be decoded
+	ErrInvalidJSON = errors.New("invalid JSON")
+)
+
+// maxErrValue is the max number of bytes of the value included in
+// the CoercionError message
+const maxErrValue = 64
+
+// CoercionError is returned when a JSON value can't be decoded to a fuzzy type.
+// Use errors.Is to check the reason, e.g. errors.Is(err, ErrBoolToNumber),
+// and errors.As to get the underlying cause, e.g. *strconv.NumError
+type CoercionError struct {
+	// Kind of the JSON value
+	Kind Kind
+	// Type the value was decoded to, e.g. fuzzy.Int
+	Type string
+	// Value is the raw JSON value
+	Value []byte
+	// Err is the reason, one of the sentinel errors, e.g. ErrBoolToNumber
+	Err error
+	// Cause is the underlying error, nil if there is none
+	Cause error
+	// Path is the JSON Pointer of the value in the input, e.g. /orders/17/qty.
+	// Path and Offset are only set by Unmarshal, Decoder and DecodeValues
+	Path string
+	// Offset is the input byte offset of the value,
+	// -1 if the input is not JSON, e.g. for DecodeValues
+	Offset int64
+	// Column is the name of the database or CSV column,
+	// only set by ScanRows, ScanRow and CSVDecoder
+	Column string
+	// Line is the line number of the CSV field, only set by CSVDecoder
+	Line int
+	// Variable is the name of the environment variable,
+	// only set by DecodeEnv
+	Variable string
+	// located is true if Path and Offset are set
+	located bool
+}
+
+// Error method for CoercionError
+func (e *CoercionError) Error() string {
+	value := string(e.Value)
+	if len(value) > maxErrValue {
+		value = value[:maxErrValue] + "..."
+	}
+	var sb strings.Builder
+	sb.WriteString("fuzzy: cannot decode ")
+	sb.WriteString(e.Kind.String())
+	if value != "" {
+		sb.WriteString(" ")
+		sb.WriteString(value)
+	}
+	if e.Type != "" {
+		sb.WriteString(" to ")
+		sb.WriteString(e.Type)
+	}
+	if e.Column != "" {
+		sb.WriteString(" in column ")
+		sb.WriteString(e.Column)
+	}
+	if e.Line > 0 {
+		sb.WriteString(" on line ")
+		sb.WriteString(strconv.Itoa(e.Line))
+	}
+	if e.Variable != "" {
+		sb.WriteString(" in variable ")
+		sb.WriteString(e.Variable)
+	}
+	if e.located {
+		sb.WriteString(" at ")
+		if e.Path == "" {
+			// The empty JSON Pointer is the whole document
+			sb.WriteString("/")
+		} else {
+			sb.WriteString(e.Path)
+		}
+		if e.Offset >= 0 {
+			sb.WriteString(" (offset ")
+			sb.WriteString(strconv.FormatInt(e.Offset, 10))
+			sb.WriteString(")")
+		}
+	}
+	if e.Err != nil {
+		sb.WriteString(": ")
+		sb.WriteString(e.Err.Error())
+	}
+	if e.Cause != nil {
+		sb.WriteString(": ")
+		sb.WriteString(e.Cause.Error())
+	}
+	return sb.String()
+}
+
+// Unwrap method for CoercionError,
+// errors.Is and errors.As match the reason and the cause
+func (e *CoercionError) Unwrap() []error {
+	errs := make([]error, 0, 2)
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	if e.Cause != nil {
+		errs = append(errs, e.Cause)
+	}
+	return errs
+}
+
+// Errors is returned when decoding with Decoder.CollectErrors,
+// it lists every value that could not be decoded
+type Errors []*CoercionError
+
+// Error method for Errors
+func (e Errors) Error() string {
+	var sb strings.Builder
+	sb.WriteString("fuzzy: ")
+	sb.WriteString(strconv.Itoa(len(e)))
+	if len(e) == 1 {
+		sb.WriteString(" error")
+	} else {
+		sb.WriteString(" errors")
+	}
+	for _, err := range e {
+		sb.WriteString("\n")
+		sb.WriteString(err.Error())
+	}
+	return sb.String()
+}
+
+// Unwrap method for Errors,
+// errors.Is and errors.As match any of the errors
+func (e Errors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// coercionError for a value of kind k.
+// Type and Value are set by the caller
+func coercionError(k Kind, reason error, cause error) *CoercionError {
+	return &CoercionError{Kind: k, Err: reason, Cause: cause}
+}
+
+// numberError converts a strconv error to a CoercionError
+func numberError(k Kind, err error) *CoercionError {
+	if errors.Is(err, strconv.ErrRange) {
+		return coercionError(k, ErrOutOfRange, err)
+	}
+	if k == KindString {
+		return coercionError(k, ErrInvalidNumberString, err)
+	}
+	return coercionError(k, ErrInvalidJSON, err)
+}
+
+// kindError for values that can't be decoded to a scalar
+func kindError(k Kind) *CoercionError {
+	if k == KindInvalid {
+		return coercionError(k, ErrInvalidJSON, nil)
+	}
+	return coercionError(k, ErrUnsupportedKind, nil)
+}
+
+// aliasNames of the Value and Null types that have an alias,
+// e.g. fuzzy.Time rather than fuzzy.Value[time.Time]
+var aliasNames = map[reflect.Type]string{
+	reflect.TypeFor[Time]():         "fuzzy.Time",
+	reflect.TypeFor[NullTime]():     "fuzzy.NullTime",
+	reflect.TypeFor[NullDate]():     "fuzzy.NullDate",
+	reflect.TypeFor[Duration]():     "fuzzy.Duration",
+	reflect.TypeFor[NullDuration](): "fuzzy.NullDuration",
+	reflect.TypeFor[NullDecimal]():  "fuzzy.NullDecimal",
+	reflect.TypeFor[NullBigInt]():   "fuzzy.NullBigInt",
+}
+
+// typeName of the value dst points to, e.g. fuzzy.Int
+func typeName(dst any) string {
+	t := reflect.TypeOf(dst)
+	if t == nil {
+		return ""
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if name, ok := aliasNames[t]; ok {
+		return name
+	}
+	return t.String()
+}
+
+// withValue sets the type and raw value on a CoercionError.
+// The value is copied, bArr may be reused by the caller
+func withValue(err *CoercionError, dst any, bArr []byte) error {
+	err.Type = typeName(dst)
+	err.Value = append([]byte(nil), bArr...)
+	return err
+}
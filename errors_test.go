@@ -0,0 +1,120 @@
+package fuzzy_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mozey/fuzzy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoercionError(t *testing.T) {
+	type Data struct {
+		Int   fuzzy.Int       `json:"int"`
+		Float fuzzy.NullFloat `json:"float"`
+		Value fuzzy.Value[bool]
+	}
+	d := Data{}
+
+	// Reason and cause
+	err := json.Unmarshal([]byte(`{"int": "abc"}`), &d)
+	require.Error(t, err)
+	var cErr *fuzzy.CoercionError
+	require.True(t, errors.As(err, &cErr), "err must be a CoercionError")
+	require.Equal(t, fuzzy.KindString, cErr.Kind)
+	require.Equal(t, "fuzzy.Int", cErr.Type)
+	require.Equal(t, `"abc"`, string(cErr.Value))
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	var numErr *strconv.NumError
+	require.True(t, errors.As(err, &numErr), "cause must be a NumError")
+	require.Equal(t, "ParseInt", numErr.Func)
+
+	// Out of range
+	err = json.Unmarshal([]byte(`{"int": "99999999999999999999"}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrOutOfRange)
+	require.ErrorIs(t, err, strconv.ErrRange)
+
+	err = json.Unmarshal([]byte(`{"float": 1e400}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrOutOfRange)
+	require.True(t, errors.As(err, &cErr))
+	require.Equal(t, fuzzy.KindNumber, cErr.Kind)
+	require.Equal(t, "fuzzy.NullFloat", cErr.Type)
+
+	// Unsupported kind
+	err = json.Unmarshal([]byte(`{"Value": {"a": 1}}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrUnsupportedKind)
+	require.True(t, errors.As(err, &cErr))
+	require.Equal(t, fuzzy.KindObject, cErr.Kind)
+	require.Equal(t, "fuzzy.Value[bool]", cErr.Type)
+	require.Nil(t, cErr.Cause)
+	require.Equal(t,
+		`fuzzy: cannot decode object {"a": 1} to fuzzy.Value[bool]: unsupported kind`,
+		err.Error())
+
+	err = json.Unmarshal([]byte(`{"int": [1, 2]}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrUnsupportedKind)
+
+	// Long values are truncated in the message, but not in the error
+	long := `"` + strings.Repeat("a", 100) + `"`
+	var i fuzzy.Int
+	err = i.UnmarshalJSON([]byte(long))
+	require.True(t, errors.As(err, &cErr))
+	require.Equal(t, long, string(cErr.Value))
+	require.True(t, strings.HasPrefix(err.Error(),
+		`fuzzy: cannot decode string "`+strings.Repeat("a", 63)+"... to fuzzy.Int"))
+}
+
+func TestCoercionErrorAliasNames(t *testing.T) {
+	for _, tc := range []struct {
+		dst  any
+		name string
+	}{
+		{&struct{ V fuzzy.Time }{}, "fuzzy.Time"},
+		{&struct{ V fuzzy.NullTime }{}, "fuzzy.NullTime"},
+		{&struct{ V fuzzy.NullDate }{}, "fuzzy.NullDate"},
+		{&struct{ V fuzzy.Duration }{}, "fuzzy.Duration"},
+		{&struct{ V fuzzy.NullDuration }{}, "fuzzy.NullDuration"},
+		{&struct{ V fuzzy.NullDecimal }{}, "fuzzy.NullDecimal"},
+		{&struct{ V fuzzy.NullBigInt }{}, "fuzzy.NullBigInt"},
+		{&struct{ V fuzzy.Null[bool] }{}, "fuzzy.Null[bool]"},
+	} {
+		err := fuzzy.Unmarshal([]byte(`{"V": {}}`), tc.dst)
+		var cErr *fuzzy.CoercionError
+		require.True(t, errors.As(err, &cErr), tc.name)
+		require.Equal(t, tc.name, cErr.Type, "type must match")
+		require.Contains(t, err.Error(),
+			"fuzzy: cannot decode object {} to "+tc.name+" at /V", "message must match")
+
+		p := fuzzy.NewProblem(err)
+		require.Equal(t, tc.name, p.InvalidParams[0].Type, "type must match")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var i fuzzy.Int
+	var f fuzzy.Float
+	errs := fuzzy.Errors{}
+	var cErr *fuzzy.CoercionError
+	require.True(t, errors.As(i.UnmarshalJSON([]byte(`true`)), &cErr))
+	errs = append(errs, cErr)
+	require.Equal(t,
+		"fuzzy: 1 error\n"+
+			"fuzzy: cannot decode bool true to fuzzy.Int: "+
+			"bool can't be decoded to a number",
+		errs.Error())
+
+	require.True(t, errors.As(f.UnmarshalJSON([]byte(`[]`)), &cErr))
+	errs = append(errs, cErr)
+	require.Equal(t,
+		"fuzzy: 2 errors\n"+
+			"fuzzy: cannot decode bool true to fuzzy.Int: "+
+			"bool can't be decoded to a number\n"+
+			"fuzzy: cannot decode array [] to fuzzy.Float: unsupported kind",
+		errs.Error())
+	require.ErrorIs(t, errs, fuzzy.ErrBoolToNumber)
+	require.ErrorIs(t, errs, fuzzy.ErrUnsupportedKind)
+	require.NotErrorIs(t, errs, fuzzy.ErrOutOfRange)
+}
@@ -0,0 +1,256 @@
+package fuzzy
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// field of a struct, as found by cachedFields
+type field struct {
+	// name of the field, from the tag or the Go field name
+	name string
+	// index sequence for reflect.Value.FieldByIndex
+	index []int
+	typ   reflect.Type
+	// tagged is true if the name came from the tag
+	tagged bool
+	// opts is the remainder of the tag after the name
+	opts tagOptions
+	// fuzzy options from the fuzzy tag, nil if there is none
+	fuzzy *fieldOptions
+	// tag of the struct field, e.g. for the default tag
+	tag reflect.StructTag
+	// parent is the struct type the field was found in by typeFields
+	parent reflect.Type
+}
+
+// tagOptions is the comma separated list of options following the name,
+// e.g. "omitempty,string"
+type tagOptions string
+
+// Contains returns true if the option is in the list
+func (o tagOptions) Contains(option string) bool {
+	s := string(o)
+	for s != "" {
+		var next string
+		s, next, _ = strings.Cut(s, ",")
+		if s == option {
+			return true
+		}
+		s = next
+	}
+	return false
+}
+
+// parseTag splits a struct tag into its name and options
+func parseTag(tag string) (string, tagOptions) {
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, tagOptions(opts)
+}
+
+type fieldsKey struct {
+	t   reflect.Type
+	tag string
+}
+
+var fieldCache sync.Map // map[fieldsKey][]field
+
+// cachedFields is like typeFields but uses a cache to avoid repeated work
+func cachedFields(t reflect.Type, tag string) []field {
+	key := fieldsKey{t: t, tag: tag}
+	if f, ok := fieldCache.Load(key); ok {
+		return f.([]field)
+	}
+	f, _ := fieldCache.LoadOrStore(key, typeFields(t, tag))
+	return f.([]field)
+}
+
+// typeFields returns the fields of struct type t, named by the given tag.
+// The tag may be a comma separated list of keys, e.g. "form,json",
+// the first key present on a field is used.
+// Fields of embedded structs are promoted following the same rules as
+// encoding/json, the shallowest field wins, and fields with the same name
+// at the same depth cancel each other out unless exactly one is tagged
+func typeFields(t reflect.Type, tag string) []field {
+	var fields []field
+	current := []field{}
+	next := []field{{typ: t}}
+	visited := map[reflect.Type]bool{}
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous {
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+				s := lookupTag(sf.Tag, tag)
+				if s == "-" {
+					continue
+				}
+				name, opts := parseTag(s)
+
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					if !sf.IsExported() {
+						continue
+					}
+					tagged := name != ""
+					if name == "" {
+						name = sf.Name
+					}
+					fields = append(fields, field{
+						name:   name,
+						index:  index,
+						typ:    sf.Type,
+						tagged: tagged,
+						opts:   opts,
+						fuzzy:  parseFieldOptions(sf.Tag.Get("fuzzy")),
+						tag:    sf.Tag,
+						parent: t,
+					})
+					continue
+				}
+
+				// Fields of the embedded struct are visited on the next level
+				next = append(next, field{name: ft.Name(), index: index, typ: ft})
+			}
+		}
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].name != fields[j].name {
+			return fields[i].name < fields[j].name
+		}
+		if len(fields[i].index) != len(fields[j].index) {
+			return len(fields[i].index) < len(fields[j].index)
+		}
+		return fields[i].tagged && !fields[j].tagged
+	})
+
+	// Keep the dominant field for each name
+	out := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if f, ok := dominantField(fields[i:j]); ok {
+			out = append(out, f)
+		}
+		i = j
+	}
+	fields = out
+
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i].index, fields[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return fields
+}
+
+// lookupTag returns the value of the first of the comma separated keys
+// present in the struct tag
+func lookupTag(st reflect.StructTag, keys string) string {
+	for _, key := range strings.Split(keys, ",") {
+		if s, ok := st.Lookup(key); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// dominantField of fields with the same name, sorted by depth and tag
+func dominantField(fields []field) (field, bool) {
+	if len(fields) > 1 &&
+		len(fields[0].index) == len(fields[1].index) &&
+		fields[0].tagged == fields[1].tagged {
+		return field{}, false
+	}
+	return fields[0], true
+}
+
+// lookupField by name, exact matches are preferred,
+// otherwise the match is case-insensitive like encoding/json
+func lookupField(fields []field, name string) *field {
+	for i := range fields {
+		if fields[i].name == name {
+			return &fields[i]
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].name, name) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// value of the field in struct v, nil embedded pointers are allocated.
+// It errors if an embedded pointer to an unexported struct is nil
+func (f *field) value(v reflect.Value) (reflect.Value, error) {
+	fv := fieldByIndex(v, f.index)
+	if !fv.IsValid() {
+		return fv, errors.Errorf(
+			"fuzzy: cannot set embedded pointer to unexported struct: %v", f.parent)
+	}
+	return fv, nil
+}
+
+// options returns opts with the fuzzy tag of the field applied,
+// it errors if the tag is not valid
+func (f *field) options(opts Options) (Options, error) {
+	if f.fuzzy == nil {
+		return opts, nil
+	}
+	if f.fuzzy.err != nil {
+		return opts, errors.Errorf("fuzzy: invalid tag on field %s of %v: %v",
+			f.name, f.parent, f.fuzzy.err)
+	}
+	return f.fuzzy.apply(opts), nil
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex,
+// but allocates nil pointers to embedded structs.
+// The returned value is invalid if an embedded pointer can't be set
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
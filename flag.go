@@ -0,0 +1,241 @@
+package fuzzy
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Set implements the flag.Value interface for String
+func (fs *String) Set(s string) error {
+	return fs.UnmarshalText([]byte(s))
+}
+
+// String implements the flag.Value interface for String
+func (fs String) String() string {
+	return string(fs)
+}
+
+// Set implements the flag.Value interface for Int,
+// e.g. -limit=1e3 is 1000 like the JSON number 1e3
+func (fi *Int) Set(s string) error {
+	return setFlag(fi, s)
+}
+
+// String implements the flag.Value interface for Int
+func (fi Int) String() string {
+	return string(encodeText(int64(fi)))
+}
+
+// Set implements the flag.Value interface for Float
+func (fi *Float) Set(s string) error {
+	return setFlag(fi, s)
+}
+
+// String implements the flag.Value interface for Float
+func (fi Float) String() string {
+	return string(encodeText(float64(fi)))
+}
+
+// Set implements the flag.Value interface for Bool
+func (fb *Bool) Set(s string) error {
+	return setFlag(fb, s)
+}
+
+// String implements the flag.Value interface for Bool
+func (fb Bool) String() string {
+	return string(encodeText(bool(fb)))
+}
+
+// IsBoolFlag makes -verbose the same as -verbose=true
+func (fb *Bool) IsBoolFlag() bool {
+	return true
+}
+
+// Set implements the flag.Value interface for NullInt,
+// the value is not valid unless the flag is set
+func (fi *NullInt) Set(s string) error {
+	return setFlag(fi, s)
+}
+
+// String implements the flag.Value interface for NullInt
+func (fi NullInt) String() string {
+	text, _ := fi.MarshalText()
+	return string(text)
+}
+
+// Set implements the flag.Value interface for NullFloat,
+// the value is not valid unless the flag is set
+func (fi *NullFloat) Set(s string) error {
+	return setFlag(fi, s)
+}
+
+// String implements the flag.Value interface for NullFloat
+func (fi NullFloat) String() string {
+	text, _ := fi.MarshalText()
+	return string(text)
+}
+
+// Set implements the flag.Value interface for NullBool,
+// the value is not valid unless the flag is set
+func (fb *NullBool) Set(s string) error {
+	return setFlag(fb, s)
+}
+
+// String implements the flag.Value interface for NullBool
+func (fb NullBool) String() string {
+	text, _ := fb.MarshalText()
+	return string(text)
+}
+
+// IsBoolFlag makes -verbose the same as -verbose=true
+func (fb *NullBool) IsBoolFlag() bool {
+	return true
+}
+
+// setFlag decodes flag value s to u like a CSV field,
+// values that are valid JSON numbers are decoded like JSON numbers
+func setFlag(u textUnmarshaler, s string) error {
+	k, text := textKind([]byte(s))
+	return u.unmarshalText(k, text, &defaultOptions)
+}
+
+// Set implements the flag.Value interface for Value
+func (fv *Value[T]) Set(s string) error {
+	return setFlag(fv, s)
+}
+
+// String implements the flag.Value interface for Value
+func (fv Value[T]) String() string {
+	return string(encodeText(fv.V))
+}
+
+// IsBoolFlag is true if T is bool
+func (fv *Value[T]) IsBoolFlag() bool {
+	_, ok := any(fv.V).(bool)
+	return ok
+}
+
+// Set implements the flag.Value interface for Null,
+// the value is not valid unless the flag is set
+func (fn *Null[T]) Set(s string) error {
+	return setFlag(fn, s)
+}
+
+// String implements the flag.Value interface for Null
+func (fn Null[T]) String() string {
+	text, _ := fn.MarshalText()
+	return string(text)
+}
+
+// IsBoolFlag is true if T is bool
+func (fn *Null[T]) IsBoolFlag() bool {
+	_, ok := any(fn.V).(bool)
+	return ok
+}
+
+// FlagSet defines a flag on fs for every field of the struct pointed to by v.
+// Flags are named by the flag tag, or the lower case field name,
+// and the usage is the usage tag. Flags of nested structs are prefixed by
+// the name of the struct field and a dot, e.g.
+//
+//	type Config struct {
+//		Limit   fuzzy.NullInt `flag:"limit" usage:"max number of items"`
+//		Verbose bool          `flag:"verbose"`
+//		DB      struct {
+//			URL string `flag:"url"`
+//		} `flag:"db"`
+//	}
+//	err := fuzzy.FlagSet(flag.CommandLine, &cfg)
+//
+// defines -limit, -verbose and -db.url.
+// The defaults are the values of the fields when FlagSet is called.
+// Values are decoded like CSV fields, i.e. valid JSON numbers like
+// JSON numbers and other values like JSON strings,
+// and the fuzzy tag applies. Slices append repeated flags,
+// and time.Duration fields accept durations like -timeout=5s
+func FlagSet(fs *flag.FlagSet, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("fuzzy: FlagSet needs a pointer to a struct, not %T", v)
+	}
+	return flagSet(fs, "", rv.Elem())
+}
+
+// flagSet defines the flags for the fields of struct v
+func flagSet(fs *flag.FlagSet, prefix string, v reflect.Value) error {
+	for _, f := range cachedFields(v.Type(), "flag") {
+		name := f.name
+		if !f.tagged {
+			name = strings.ToLower(name)
+		}
+		name = prefix + name
+		fv, err := f.value(v)
+		if err != nil {
+			return err
+		}
+		opts, err := f.options(defaultOptions)
+		if err != nil {
+			return err
+		}
+
+		for fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct && !isText(fv) {
+			if err := flagSet(fs, name+".", fv); err != nil {
+				return err
+			}
+			continue
+		}
+		fs.Var(&flagValue{v: fv, opts: opts}, name, f.tag.Get("usage"))
+	}
+	return nil
+}
+
+// flagValue is a flag.Value for a struct field, see FlagSet
+type flagValue struct {
+	v    reflect.Value
+	opts Options
+	// values of a slice, set so far
+	values []string
+}
+
+// Set decodes s to the field
+func (f *flagValue) Set(s string) error {
+	values := []string{s}
+	if f.v.Kind() == reflect.Slice && f.v.Type().Elem().Kind() != reflect.Uint8 {
+		f.values = append(f.values, s)
+		values = f.values
+	}
+	d := decodeState{opts: f.opts, numbers: true}
+	return unlocate(d.form(&formNode{values: values}, f.v))
+}
+
+// String formats the field, it's called on the zero value by flag.PrintDefaults
+func (f *flagValue) String() string {
+	if f == nil || !f.v.IsValid() {
+		return ""
+	}
+	s, _ := formatText(f.v)
+	return s
+}
+
+// IsBoolFlag is true for bools, e.g. bool, fuzzy.Bool and sql.NullBool
+func (f *flagValue) IsBoolFlag() bool {
+	if b, ok := f.v.Addr().Interface().(interface{ IsBoolFlag() bool }); ok {
+		return b.IsBoolFlag()
+	}
+	if b, ok := fuzzyNull[interface{ IsBoolFlag() bool }](f.v); ok {
+		return b.IsBoolFlag()
+	}
+	if isSQLNull(f.v.Type()) {
+		return f.v.Field(0).Kind() == reflect.Bool
+	}
+	return f.v.Kind() == reflect.Bool
+}
@@ -0,0 +1,164 @@
+package fuzzy_test
+
+import (
+	"bytes"
+	"database/sql"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+// Interfaces must be implemented
+var (
+	_ flag.Value = (*fuzzy.String)(nil)
+	_ flag.Value = (*fuzzy.Int)(nil)
+	_ flag.Value = (*fuzzy.Float)(nil)
+	_ flag.Value = (*fuzzy.Bool)(nil)
+	_ flag.Value = (*fuzzy.NullInt)(nil)
+	_ flag.Value = (*fuzzy.NullFloat)(nil)
+	_ flag.Value = (*fuzzy.NullBool)(nil)
+	_ flag.Value = (*fuzzy.Value[int64])(nil)
+	_ flag.Value = (*fuzzy.Null[bool])(nil)
+)
+
+func TestFlagValue(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	var (
+		name    fuzzy.String
+		limit   fuzzy.NullInt
+		offset  fuzzy.NullInt
+		ratio   fuzzy.Float
+		verbose fuzzy.Bool
+		debug   fuzzy.NullBool
+		count   fuzzy.Value[int64]
+		dryRun  fuzzy.Null[bool]
+	)
+	fs.Var(&name, "name", "")
+	fs.Var(&limit, "limit", "")
+	fs.Var(&offset, "offset", "")
+	fs.Var(&ratio, "ratio", "")
+	fs.Var(&verbose, "verbose", "")
+	fs.Var(&debug, "debug", "")
+	fs.Var(&count, "count", "")
+	fs.Var(&dryRun, "dry-run", "")
+	err := fs.Parse([]string{
+		"-name=a", "-limit=0", "-ratio=1e3", "-verbose=yes", "-debug",
+		"-count", "2", "-dry-run",
+	})
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.String("a"), name)
+	require.Equal(t, fuzzy.NullInt(null.IntFrom(0)), limit, "provided as 0")
+	require.False(t, offset.Valid, "not provided")
+	require.Equal(t, fuzzy.Float(1000), ratio)
+	require.Equal(t, fuzzy.Bool(true), verbose)
+	require.Equal(t, fuzzy.NullBool(null.BoolFrom(true)), debug)
+	require.Equal(t, int64(2), count.V)
+	require.Equal(t, fuzzy.Null[bool]{V: true, Valid: true}, dryRun)
+
+	require.Equal(t, "0", limit.String())
+	require.Equal(t, "", offset.String())
+	require.Equal(t, "1000", ratio.String())
+
+	// Numbers are decoded like JSON numbers
+	err = fs.Parse([]string{"-limit=1e3", "-count=1e3"})
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.NullInt(null.IntFrom(1000)), limit)
+	require.Equal(t, int64(1000), count.V)
+
+	// Bools are decoded the same by all the bool types
+	for _, in := range []string{"0.0", "1e0", "yes", "false"} {
+		var b fuzzy.Bool
+		var nb fuzzy.NullBool
+		var vb fuzzy.Value[bool]
+		var nvb fuzzy.Null[bool]
+		require.NoError(t, b.Set(in), in)
+		require.NoError(t, nb.Set(in), in)
+		require.NoError(t, vb.Set(in), in)
+		require.NoError(t, nvb.Set(in), in)
+		require.Equal(t, vb.V, bool(b), "value must match for %s", in)
+		require.Equal(t, vb.V, nb.Bool, "value must match for %s", in)
+		require.Equal(t, vb.V, nvb.V, "value must match for %s", in)
+	}
+	require.NoError(t, verbose.Set("0.0"))
+	require.Equal(t, fuzzy.Bool(false), verbose, "0.0 must be false")
+
+	err = fs.Parse([]string{"-limit=abc"})
+	require.EqualError(t, err,
+		`invalid value "abc" for flag -limit: fuzzy: cannot decode string abc to fuzzy.NullInt: string is not a valid number: strconv.ParseInt: parsing "abc": invalid syntax`)
+}
+
+type FlagConfig struct {
+	Limit   fuzzy.NullInt    `flag:"limit" usage:"max number of items"`
+	Name    fuzzy.NullString `flag:"name"`
+	Verbose bool             `flag:"verbose"`
+	Ratio   float64          `fuzzy:"trim"`
+	Active  bool             `flag:"active" fuzzy:"true=y,false=n"`
+	Hosts   []string         `flag:"host" usage:"repeat for more hosts"`
+	Stock   sql.NullInt32    `flag:"stock"`
+	Count   null.Int         `flag:"count"`
+	Timeout time.Duration    `flag:"timeout"`
+	DB      struct {
+		URL string `flag:"url"`
+	} `flag:"db"`
+	Ignored int `flag:"-"`
+}
+
+func TestFlagSet(t *testing.T) {
+	cfg := FlagConfig{Hosts: []string{"default"}}
+	cfg.DB.URL = "postgres://localhost"
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	out := &bytes.Buffer{}
+	fs.SetOutput(out)
+	require.NoError(t, fuzzy.FlagSet(fs, &cfg))
+
+	err := fs.Parse([]string{
+		"-name", "", "-verbose", "-ratio= 0.5 ", "-active=Y",
+		"-host=a", "-host=b", "-stock=3", "-count=4", "-db.url=x",
+	})
+	require.NoError(t, err)
+	expected := FlagConfig{
+		Name:    fuzzy.NullString(null.StringFrom("")),
+		Verbose: true,
+		Ratio:   0.5,
+		Active:  true,
+		Hosts:   []string{"a", "b"},
+		Stock:   sql.NullInt32{Int32: 3, Valid: true},
+		Count:   null.IntFrom(4),
+	}
+	expected.DB.URL = "x"
+	require.Equal(t, expected, cfg)
+
+	err = fs.Parse([]string{"-limit=1e3", "-stock=2.0"})
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.NullInt(null.IntFrom(1000)), cfg.Limit)
+	require.Equal(t, sql.NullInt32{Int32: 2, Valid: true}, cfg.Stock)
+
+	// Durations are like flag.DurationVar
+	err = fs.Parse([]string{"-timeout=1m30s"})
+	require.NoError(t, err)
+	require.Equal(t, 90*time.Second, cfg.Timeout, "value must match")
+	err = fs.Parse([]string{"-timeout=PT5S"})
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, cfg.Timeout, "value must match")
+	err = fs.Parse([]string{"-timeout=soon"})
+	require.ErrorContains(t, err, "cannot decode string soon to time.Duration")
+
+	err = fs.Parse([]string{"-active=maybe"})
+	require.EqualError(t, err,
+		`invalid boolean value "maybe" for -active: fuzzy: cannot decode string maybe to bool: string is not a valid bool`)
+
+	fs.PrintDefaults()
+	require.Contains(t, out.String(), "-limit value\n    \tmax number of items")
+	require.Contains(t, out.String(), "-host value\n    \trepeat for more hosts")
+	require.Contains(t, out.String(), "-verbose\n")
+	require.Contains(t, out.String(), `-db.url value`+"\n    \t (default postgres://localhost)")
+
+	err = fuzzy.FlagSet(fs, cfg)
+	require.EqualError(t, err,
+		"fuzzy: FlagSet needs a pointer to a struct, not fuzzy_test.FlagConfig")
+}
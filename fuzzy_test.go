@@ -101,8 +101,10 @@ func TestInt(t *testing.T) {
 	b = []byte(`{"int": "abc"}`)
 	err = json.Unmarshal(b, &d)
 	require.Error(t, err)
-	// TODO Add context to err message
-	// TODO Check err message
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	require.Equal(t,
+		`fuzzy: cannot decode string "abc" to fuzzy.Int: string is not a valid number: strconv.ParseInt: parsing "abc": invalid syntax`,
+		err.Error())
 
 	// int
 	b = []byte(`{"int": -123}`)
@@ -120,8 +122,10 @@ func TestInt(t *testing.T) {
 	b = []byte(`{"int": true}`)
 	err = json.Unmarshal(b, &d)
 	require.Error(t, err)
-	// TODO Add context to err message
-	// TODO Check err message
+	require.ErrorIs(t, err, fuzzy.ErrBoolToNumber)
+	require.Equal(t,
+		`fuzzy: cannot decode bool true to fuzzy.Int: bool can't be decoded to a number`,
+		err.Error())
 }
 
 func TestFloat(t *testing.T) {
@@ -155,8 +159,10 @@ func TestFloat(t *testing.T) {
 	b = []byte(`{"float": "abc"}`)
 	err = json.Unmarshal(b, &d)
 	require.Error(t, err)
-	// TODO Add context to err message
-	// TODO Check err message
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	require.Equal(t,
+		`fuzzy: cannot decode string "abc" to fuzzy.Float: string is not a valid number: strconv.ParseFloat: parsing "abc": invalid syntax`,
+		err.Error())
 
 	// int
 	b = []byte(`{"float": -1}`)
@@ -174,8 +180,10 @@ func TestFloat(t *testing.T) {
 	b = []byte(`{"float": true}`)
 	err = json.Unmarshal(b, &d)
 	require.Error(t, err)
-	// TODO Add context to err message
-	// TODO Check err message
+	require.ErrorIs(t, err, fuzzy.ErrBoolToNumber)
+	require.Equal(t,
+		`fuzzy: cannot decode bool true to fuzzy.Float: bool can't be decoded to a number`,
+		err.Error())
 }
 
 func TestBool(t *testing.T) {
@@ -312,3 +320,48 @@ func TestMarshalToJSON(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, `{}`, string(b))
 }
+
+// benchmarkValues has one input per JSON kind that can be decoded
+var benchmarkValues = []struct {
+	name string
+	bArr []byte
+}{
+	{"null", []byte(`null`)},
+	{"string", []byte(`"123"`)},
+	{"int", []byte(`-123`)},
+	{"float", []byte(`-123.456`)},
+	{"bool", []byte(`false`)},
+}
+
+// benchmarkUnmarshal runs UnmarshalJSON on newV() for each benchmark value.
+// Values that are not valid for the type are skipped
+func benchmarkUnmarshal(b *testing.B, newV func() json.Unmarshaler) {
+	for _, bv := range benchmarkValues {
+		if err := newV().UnmarshalJSON(bv.bArr); err != nil {
+			continue
+		}
+		b.Run(bv.name, func(b *testing.B) {
+			v := newV()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = v.UnmarshalJSON(bv.bArr)
+			}
+		})
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	benchmarkUnmarshal(b, func() json.Unmarshaler { return new(fuzzy.String) })
+}
+
+func BenchmarkInt(b *testing.B) {
+	benchmarkUnmarshal(b, func() json.Unmarshaler { return new(fuzzy.Int) })
+}
+
+func BenchmarkFloat(b *testing.B) {
+	benchmarkUnmarshal(b, func() json.Unmarshaler { return new(fuzzy.Float) })
+}
+
+func BenchmarkBool(b *testing.B) {
+	benchmarkUnmarshal(b, func() json.Unmarshaler { return new(fuzzy.Bool) })
+}
@@ -1,16 +1,12 @@
 package fuzzy
 
 import (
-	"encoding/json"
-	"fmt"
-	"strconv"
-	"strings"
-
 	"github.com/guregu/null"
-	"github.com/pkg/errors"
 )
 
-// NullString can be used to decode any JSON value to string
+// NullString can be used to decode any JSON value to string.
+// NullString does not implement flag.Value, a String method would
+// conflict with the String field, use FlagSet or flag.TextVar instead
 type NullString null.String
 
 // MarshalJSON method with value receiver for String
@@ -20,45 +16,20 @@ func (fs NullString) MarshalJSON() ([]byte, error) {
 	if !fs.Valid {
 		return []byte(`null`), nil
 	}
-	return []byte(fmt.Sprintf(`"%s"`, fs.String)), nil
+	return encode(fs.String, &defaultOptions)
 }
 
 // UnmarshalJSON for String
 func (fs *NullString) UnmarshalJSON(bArr []byte) (err error) {
-	s, i, f, b :=
-		string(""), uint64(0), float64(0), false
-
-	// Value is null
-	if string(bArr) == "null" {
-		*fs = NullString(null.String{})
-		return
-	}
-
-	// Value is a...
-	// string
-	if err = json.Unmarshal(bArr, &s); err == nil {
-		*fs = NullString(null.StringFrom(s))
-		return
-	}
-
-	// int
-	if err = json.Unmarshal(bArr, &i); err == nil {
-		*fs = NullString(null.StringFrom(string(bArr[:])))
-		return
-	}
-
-	// float
-	if err = json.Unmarshal(bArr, &f); err == nil {
-		*fs = NullString(null.StringFrom(string(bArr[:])))
-		return
-	}
+	return fs.unmarshalJSON(bArr, &defaultOptions)
+}
 
-	// bool
-	if err = json.Unmarshal(bArr, &b); err == nil {
-		*fs = NullString(null.StringFrom(string(bArr[:])))
-		return
+func (fs *NullString) unmarshalJSON(bArr []byte, opts *Options) (err error) {
+	v, valid, err := decode[string](bArr, fs, opts)
+	if err != nil {
+		return err
 	}
-
+	*fs = NullString(null.NewString(v, valid))
 	return
 }
 
@@ -72,55 +43,24 @@ func (fi NullInt) MarshalJSON() ([]byte, error) {
 	if !fi.Valid {
 		return []byte(`null`), nil
 	}
-	return []byte(strconv.FormatInt(fi.Int64, 10)), nil
+	return encode(fi.Int64, &defaultOptions)
 }
 
 // UnmarshalJSON method for Int
 func (fi *NullInt) UnmarshalJSON(bArr []byte) (err error) {
-	s, i, f, b :=
-		string(""), int64(0), float64(0), false
-
-	// Value is null
-	if string(bArr) == "null" {
-		*fi = NullInt(null.Int{})
-		return
-	}
-
-	// Value is a...
-	// string
-	if err = json.Unmarshal(bArr, &s); err == nil {
-		i, err2 := strconv.ParseInt(s, 10, 64)
-		if err2 != nil {
-			// Value is null if int could not be parsed from the string
-			//*fi = Int(null.Int{})
-			return err2
-		}
-		*fi = NullInt(null.IntFrom(i))
-		return
-	}
-
-	// int
-	if err = json.Unmarshal(bArr, &i); err == nil {
-		*fi = NullInt(null.IntFrom(i))
-		return
-	}
-
-	// float
-	if err = json.Unmarshal(bArr, &f); err == nil {
-		*fi = NullInt(null.IntFrom(int64(f)))
-		return
-	}
+	return fi.unmarshalJSON(bArr, &defaultOptions)
+}
 
-	// bool
-	if err = json.Unmarshal(bArr, &b); err == nil {
-		//*fi = Int(null.IntFrom(0))
-		return errors.WithStack(fmt.Errorf("value is a bool"))
+func (fi *NullInt) unmarshalJSON(bArr []byte, opts *Options) (err error) {
+	v, valid, err := decode[int64](bArr, fi, opts)
+	if err != nil {
+		return err
 	}
-
+	*fi = NullInt(null.NewInt(v, valid))
 	return
 }
 
-// NullFloat can be used to decode any JSON value to int64.
+// NullFloat can be used to decode any JSON value to float64.
 // Strings that are not valid representation of a number will error.
 // Boolean values will error
 type NullFloat null.Float
@@ -130,51 +70,20 @@ func (fi NullFloat) MarshalJSON() ([]byte, error) {
 	if !fi.Valid {
 		return []byte(`null`), nil
 	}
-	return []byte(strconv.FormatFloat(fi.Float64, 'f', -1, 64)), nil
+	return encode(fi.Float64, &defaultOptions)
 }
 
 // UnmarshalJSON method for Float
 func (fi *NullFloat) UnmarshalJSON(bArr []byte) (err error) {
-	s, i, f, b :=
-		string(""), int64(0), float64(0), false
-
-	// Value is null
-	if string(bArr) == "null" {
-		*fi = NullFloat(null.Float{})
-		return
-	}
-
-	// Value is a...
-	// string
-	if err = json.Unmarshal(bArr, &s); err == nil {
-		i, err2 := strconv.ParseFloat(s, 10)
-		if err2 != nil {
-			// Value is null if int could not be parsed from the string
-			//*fi = Float(null.Int{})
-			return err2
-		}
-		*fi = NullFloat(null.FloatFrom(i))
-		return
-	}
-
-	// int
-	if err = json.Unmarshal(bArr, &i); err == nil {
-		*fi = NullFloat(null.FloatFrom(float64(i)))
-		return
-	}
-
-	// float
-	if err = json.Unmarshal(bArr, &f); err == nil {
-		*fi = NullFloat(null.FloatFrom(f))
-		return
-	}
+	return fi.unmarshalJSON(bArr, &defaultOptions)
+}
 
-	// bool
-	if err = json.Unmarshal(bArr, &b); err == nil {
-		//*fi = Float(null.IntFrom(0))
-		return errors.WithStack(fmt.Errorf("value is a bool"))
+func (fi *NullFloat) unmarshalJSON(bArr []byte, opts *Options) (err error) {
+	v, valid, err := decode[float64](bArr, fi, opts)
+	if err != nil {
+		return err
 	}
-
+	*fi = NullFloat(null.NewFloat(v, valid))
 	return
 }
 
@@ -190,57 +99,19 @@ func (fb NullBool) MarshalJSON() ([]byte, error) {
 	if !fb.Valid {
 		return []byte(`null`), nil
 	}
-	return []byte(strconv.FormatBool(fb.Bool)), nil
+	return encode(fb.Bool, &defaultOptions)
 }
 
 // UnmarshalJSON method for Bool
 func (fb *NullBool) UnmarshalJSON(bArr []byte) (err error) {
-	s, i, f, b :=
-		string(""), int64(0), float64(0), false
-
-	// Value is null
-	if string(bArr) == "null" {
-		*fb = NullBool(null.Bool{})
-		return
-	}
-
-	// Value is a...
-	// string
-	if err = json.Unmarshal(bArr, &s); err == nil {
-		compare := strings.ToLower(strings.TrimSpace(s))
-		if compare == "false" || compare == "0" || compare == "" {
-			*fb = NullBool(null.BoolFrom(false))
-			return
-		}
-		*fb = NullBool(null.BoolFrom(true))
-		return
-	}
-
-	// int
-	if err = json.Unmarshal(bArr, &i); err == nil {
-		if i == 0 {
-			*fb = NullBool(null.BoolFrom(false))
-			return
-		}
-		*fb = NullBool(null.BoolFrom(true))
-		return
-	}
-
-	// float
-	if err = json.Unmarshal(bArr, &f); err == nil {
-		if f == 0 {
-			*fb = NullBool(null.BoolFrom(false))
-			return
-		}
-		*fb = NullBool(null.BoolFrom(true))
-		return
-	}
+	return fb.unmarshalJSON(bArr, &defaultOptions)
+}
 
-	// bool
-	if err = json.Unmarshal(bArr, &b); err == nil {
-		*fb = NullBool(null.BoolFrom(b))
-		return
+func (fb *NullBool) unmarshalJSON(bArr []byte, opts *Options) (err error) {
+	v, valid, err := decode[bool](bArr, fb, opts)
+	if err != nil {
+		return err
 	}
-
+	*fb = NullBool(null.NewBool(v, valid))
 	return
 }
@@ -116,8 +116,10 @@ func TestNullInt(t *testing.T) {
 	b = []byte(`{"int": "abc"}`)
 	err = json.Unmarshal(b, &d)
 	require.Error(t, err)
-	// TODO Add context to err message
-	// TODO Check err message
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	require.Equal(t,
+		`fuzzy: cannot decode string "abc" to fuzzy.NullInt: string is not a valid number: strconv.ParseInt: parsing "abc": invalid syntax`,
+		err.Error())
 
 	// int
 	b = []byte(`{"int": -123}`)
@@ -137,8 +139,10 @@ func TestNullInt(t *testing.T) {
 	b = []byte(`{"int": true}`)
 	err = json.Unmarshal(b, &d)
 	require.Error(t, err)
-	// TODO Add context to err message
-	// TODO Check err message
+	require.ErrorIs(t, err, fuzzy.ErrBoolToNumber)
+	require.Equal(t,
+		`fuzzy: cannot decode bool true to fuzzy.NullInt: bool can't be decoded to a number`,
+		err.Error())
 }
 
 func TestNullFloat(t *testing.T) {
@@ -176,8 +180,10 @@ func TestNullFloat(t *testing.T) {
 	b = []byte(`{"float": "abc"}`)
 	err = json.Unmarshal(b, &d)
 	require.Error(t, err)
-	// TODO Add context to err message
-	// TODO Check err message
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	require.Equal(t,
+		`fuzzy: cannot decode string "abc" to fuzzy.NullFloat: string is not a valid number: strconv.ParseFloat: parsing "abc": invalid syntax`,
+		err.Error())
 
 	// int
 	b = []byte(`{"float": -1}`)
@@ -197,8 +203,10 @@ func TestNullFloat(t *testing.T) {
 	b = []byte(`{"float": true}`)
 	err = json.Unmarshal(b, &d)
 	require.Error(t, err)
-	// TODO Add context to err message
-	// TODO Check err message
+	require.ErrorIs(t, err, fuzzy.ErrBoolToNumber)
+	require.Equal(t,
+		`fuzzy: cannot decode bool true to fuzzy.NullFloat: bool can't be decoded to a number`,
+		err.Error())
 }
 
 func TestNullBool(t *testing.T) {
@@ -363,3 +371,19 @@ func TestNullMarshalToJSON(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, `{"string":null,"int":null,"bool":null,"float":null}`, string(b))
 }
+
+func BenchmarkNullString(b *testing.B) {
+	benchmarkUnmarshal(b, func() json.Unmarshaler { return new(fuzzy.NullString) })
+}
+
+func BenchmarkNullInt(b *testing.B) {
+	benchmarkUnmarshal(b, func() json.Unmarshaler { return new(fuzzy.NullInt) })
+}
+
+func BenchmarkNullFloat(b *testing.B) {
+	benchmarkUnmarshal(b, func() json.Unmarshaler { return new(fuzzy.NullFloat) })
+}
+
+func BenchmarkNullBool(b *testing.B) {
+	benchmarkUnmarshal(b, func() json.Unmarshaler { return new(fuzzy.NullBool) })
+}
@@ -0,0 +1,117 @@
+package fuzzy
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Kind of JSON value
+type Kind int
+
+const (
+	// KindInvalid is not a JSON value
+	KindInvalid Kind = iota
+	KindNull
+	KindString
+	KindNumber
+	KindBool
+	KindObject
+	KindArray
+)
+
+var kindNames = [...]string{
+	KindInvalid: "invalid",
+	KindNull:    "null",
+	KindString:  "string",
+	KindNumber:  "number",
+	KindBool:    "bool",
+	KindObject:  "object",
+	KindArray:   "array",
+}
+
+// String method for Kind
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return kindNames[KindInvalid]
+	}
+	return kindNames[k]
+}
+
+// kindOf classifies the JSON value in bArr by its first byte.
+// Leading whitespace is skipped, the value itself is not validated
+func kindOf(bArr []byte) Kind {
+	for _, c := range bArr {
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '"':
+			return KindString
+		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			return KindNumber
+		case 't', 'f':
+			return KindBool
+		case 'n':
+			return KindNull
+		case '{':
+			return KindObject
+		case '[':
+			return KindArray
+		}
+		return KindInvalid
+	}
+	return KindInvalid
+}
+
+// trim leading and trailing JSON whitespace from bArr
+func trim(bArr []byte) []byte {
+	for len(bArr) > 0 && isSpace(bArr[0]) {
+		bArr = bArr[1:]
+	}
+	for len(bArr) > 0 && isSpace(bArr[len(bArr)-1]) {
+		bArr = bArr[:len(bArr)-1]
+	}
+	return bArr
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// unquote the JSON string in bArr
+func unquote(bArr []byte) (s string, err error) {
+	content, err := unquoteBytes(bArr)
+	if err != nil {
+		return s, err
+	}
+	return string(content), nil
+}
+
+// unquoteBytes returns the content of the JSON string in bArr.
+// Strings without escape sequences are sliced, not parsed
+func unquoteBytes(bArr []byte) (content []byte, err error) {
+	if len(bArr) < 2 || bArr[0] != '"' || bArr[len(bArr)-1] != '"' {
+		return content, errors.WithStack(fmt.Errorf("invalid JSON string"))
+	}
+	content = bArr[1 : len(bArr)-1]
+	for _, c := range content {
+		if c == '\\' || c == '"' || c < ' ' {
+			// Escape sequences and invalid strings are left to encoding/json
+			return unmarshalString(bArr)
+		}
+	}
+	if !utf8.Valid(content) {
+		return unmarshalString(bArr)
+	}
+	return content, nil
+}
+
+func unmarshalString(bArr []byte) (content []byte, err error) {
+	s := ""
+	if err = json.Unmarshal(bArr, &s); err != nil {
+		return content, err
+	}
+	return []byte(s), nil
+}
@@ -0,0 +1,73 @@
+package fuzzy_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKindString(t *testing.T) {
+	require.Equal(t, "null", fuzzy.KindNull.String())
+	require.Equal(t, "string", fuzzy.KindString.String())
+	require.Equal(t, "number", fuzzy.KindNumber.String())
+	require.Equal(t, "bool", fuzzy.KindBool.String())
+	require.Equal(t, "object", fuzzy.KindObject.String())
+	require.Equal(t, "array", fuzzy.KindArray.String())
+	require.Equal(t, "invalid", fuzzy.Kind(-1).String())
+}
+
+func TestUnsupportedKind(t *testing.T) {
+	for _, v := range []json.Unmarshaler{
+		new(fuzzy.String), new(fuzzy.Int), new(fuzzy.Float), new(fuzzy.Bool),
+		new(fuzzy.NullString), new(fuzzy.NullInt),
+		new(fuzzy.NullFloat), new(fuzzy.NullBool),
+	} {
+		require.Error(t, v.UnmarshalJSON([]byte(`{"a": 1}`)), "%T", v)
+		require.Error(t, v.UnmarshalJSON([]byte(`[1]`)), "%T", v)
+		require.Error(t, v.UnmarshalJSON([]byte(``)), "%T", v)
+	}
+}
+
+func TestEscapedString(t *testing.T) {
+	var s fuzzy.String
+	err := s.UnmarshalJSON([]byte(`"a\"bé"`))
+	require.NoError(t, err)
+	require.Equal(t, `a"bé`, string(s), "value must match")
+
+	var i fuzzy.Int
+	err = i.UnmarshalJSON([]byte(`"12"`))
+	require.NoError(t, err)
+	require.Equal(t, int64(12), int64(i), "value must match")
+}
+
+// TestAllocs checks that decoding is done in a single pass,
+// decoding a JSON value must not allocate more than once
+func TestAllocs(t *testing.T) {
+	var i fuzzy.Int
+	var f fuzzy.Float
+	var b fuzzy.Bool
+	var s fuzzy.String
+	for _, tc := range []struct {
+		v    json.Unmarshaler
+		bArr []byte
+		max  float64
+	}{
+		{&i, []byte(`"123"`), 0},
+		{&i, []byte(`-123`), 0},
+		{&i, []byte(`-123.456`), 0},
+		{&f, []byte(`"1.618"`), 0},
+		{&f, []byte(`1.618`), 0},
+		{&b, []byte(`"false"`), 0},
+		{&b, []byte(`true`), 0},
+		{&s, []byte(`"123"`), 1},
+		{&s, []byte(`123`), 1},
+	} {
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = tc.v.UnmarshalJSON(tc.bArr)
+		})
+		require.LessOrEqual(t, allocs, tc.max,
+			"%T %s must not allocate more than %v", tc.v, tc.bArr, tc.max)
+	}
+}
@@ -0,0 +1,203 @@
+package fuzzy
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Options for decoding and encoding fuzzy types.
+// The zero value is the default behaviour of UnmarshalJSON and MarshalJSON.
+// Options apply to a single call to UnmarshalWithOptions, Decoder.Decode,
+// MarshalWithOptions or Encoder.Encode,
+// and must not be modified while the call is in progress
+type Options struct {
+	// Lenient falls back to the zero value, i.e. invalid for the Null types,
+	// for values that can't be decoded. See Decoder.Lenient
+	Lenient bool
+	// CollectErrors continues past values that can't be decoded,
+	// see Decoder.CollectErrors
+	CollectErrors bool
+	// DisallowUnknownFields, see Decoder.DisallowUnknownFields
+	DisallowUnknownFields bool
+	// UseNumber, see Decoder.UseNumber
+	UseNumber bool
+
+	// Rounding of JSON numbers with a fraction decoded to Int,
+	// the default is to truncate
+	Rounding Rounding
+	// BoolToNumber decodes bools to 1 and 0 for Int and Float,
+	// by default bools can't be decoded to numbers
+	BoolToNumber bool
+	// BoolTrue and BoolFalse are the strings decoded to true and false
+	// for Bool, compared case-insensitively.
+	// If neither is set, "false", "0" and "" are false,
+	// and all other strings are true.
+	// If either is set, other strings can't be decoded to Bool
+	BoolTrue  []string
+	BoolFalse []string
+	// EmptyString sets how empty JSON strings are decoded
+	EmptyString EmptyString
+	// TrimSpace removes leading and trailing white space from JSON strings
+	// before they are decoded
+	TrimSpace bool
+	// TimeLayouts are tried in order to decode strings to Time,
+	// DefaultTimeLayouts are used if it's empty
+	TimeLayouts []string
+	// EpochUnit of numbers decoded to Time,
+	// the default is to detect the unit, see EpochAuto
+	EpochUnit EpochUnit
+	// DateOrder of the day and month in ambiguous dates decoded to Date,
+	// the default is DateDayFirst
+	DateOrder DateOrder
+	// DurationUnit of numbers decoded to Duration,
+	// the default is time.Second
+	DurationUnit time.Duration
+	// MaxScale is the max number of digits after the point of numbers
+	// decoded to Decimal, trailing zeros are ignored.
+	// Zero is no limit
+	MaxScale int
+	// MaxPrecision is the max number of digits of numbers decoded to
+	// Decimal, see Decimal.Precision. Zero is no limit
+	MaxPrecision int
+	// MaxSliceLen is the max length of slices decoded from bracketed
+	// indexes like items[0] in url.Values, see DecodeValues.
+	// Zero is DefaultMaxSliceLen
+	MaxSliceLen int
+
+	// TimeFormat is the layout Time is encoded with,
+	// or one of the TimeFormatUnix constants to encode Unix epochs
+	// as JSON numbers. The default is time.RFC3339Nano
+	TimeFormat string
+	// DurationFormat is the style Duration is encoded with,
+	// the default is DurationGo
+	DurationFormat DurationStyle
+	// BigIntFormat is the style BigInt is encoded with,
+	// the default is BigIntNumber
+	BigIntFormat BigIntStyle
+}
+
+// DefaultMaxSliceLen is the max length of slices decoded from indexes,
+// if Options.MaxSliceLen is zero
+const DefaultMaxSliceLen = 1000
+
+// defaultOptions are used by UnmarshalJSON.
+// Must not be modified
+var defaultOptions = Options{}
+
+// Rounding mode for numbers with a fraction decoded to Int
+type Rounding int
+
+const (
+	// RoundTruncate rounds towards zero
+	RoundTruncate Rounding = iota
+	// RoundHalfEven rounds to the nearest integer, and half to even
+	RoundHalfEven
+	// RoundHalfUp rounds to the nearest integer, and half away from zero
+	RoundHalfUp
+	// RoundFloor rounds towards negative infinity
+	RoundFloor
+	// RoundCeil rounds towards positive infinity
+	RoundCeil
+	// RoundReject does not round, numbers with a fraction can't be decoded
+	RoundReject
+)
+
+// EmptyString sets how empty JSON strings are decoded
+type EmptyString int
+
+const (
+	// EmptyDefault decodes empty strings like any other string,
+	// i.e. to "" for String, false for Bool, and an error for Int and Float
+	EmptyDefault EmptyString = iota
+	// EmptyZero decodes empty strings to the zero value,
+	// the value is valid for the Null types
+	EmptyZero
+	// EmptyNull decodes empty strings like null
+	EmptyNull
+)
+
+// round f to an int64
+func (o *Options) round(f float64) (int64, error) {
+	switch o.Rounding {
+	case RoundHalfEven:
+		f = math.RoundToEven(f)
+	case RoundHalfUp:
+		f = math.Round(f)
+	case RoundFloor:
+		f = math.Floor(f)
+	case RoundCeil:
+		f = math.Ceil(f)
+	case RoundReject:
+		if f != math.Trunc(f) {
+			return 0, ErrFraction
+		}
+	default:
+		f = math.Trunc(f)
+	}
+	i, ok := floatToInt64(f)
+	if !ok {
+		return 0, ErrOutOfRange
+	}
+	return i, nil
+}
+
+// parseBool decodes string s to a bool
+func (o *Options) parseBool(s string) (b bool, ok bool) {
+	compare := strings.TrimSpace(s)
+	if len(o.BoolTrue) == 0 && len(o.BoolFalse) == 0 {
+		compare = strings.ToLower(compare)
+		return !(compare == "false" || compare == "0" || compare == ""), true
+	}
+	for _, t := range o.BoolTrue {
+		if strings.EqualFold(compare, t) {
+			return true, true
+		}
+	}
+	for _, f := range o.BoolFalse {
+		if strings.EqualFold(compare, f) {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// timeLayouts to decode strings to Time
+func (o *Options) timeLayouts() []string {
+	if len(o.TimeLayouts) == 0 {
+		return defaultTimeLayouts
+	}
+	return o.TimeLayouts
+}
+
+// dateOrder to decode ambiguous dates
+func (o *Options) dateOrder() DateOrder {
+	if o.DateOrder == DateMonthFirst {
+		return DateMonthFirst
+	}
+	return DateDayFirst
+}
+
+// durationUnit of numbers decoded to Duration
+func (o *Options) durationUnit() time.Duration {
+	if o.DurationUnit <= 0 {
+		return time.Second
+	}
+	return o.DurationUnit
+}
+
+// maxSliceLen of slices decoded from indexes
+func (o *Options) maxSliceLen() int {
+	if o.MaxSliceLen <= 0 {
+		return DefaultMaxSliceLen
+	}
+	return o.MaxSliceLen
+}
+
+// timeFormat of Time encoded to JSON
+func (o *Options) timeFormat() string {
+	if o.TimeFormat == "" {
+		return time.RFC3339Nano
+	}
+	return o.TimeFormat
+}
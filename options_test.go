@@ -0,0 +1,208 @@
+package fuzzy_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsRounding(t *testing.T) {
+	type Data struct {
+		Int fuzzy.Int `json:"int"`
+	}
+	for _, tc := range []struct {
+		rounding fuzzy.Rounding
+		in       []string
+		out      []int64
+	}{
+		{fuzzy.RoundTruncate,
+			[]string{"1.5", "2.5", "-1.5", "1.7", "-1.7"},
+			[]int64{1, 2, -1, 1, -1}},
+		{fuzzy.RoundHalfEven,
+			[]string{"1.5", "2.5", "-1.5", "1.7", "-1.7"},
+			[]int64{2, 2, -2, 2, -2}},
+		{fuzzy.RoundHalfUp,
+			[]string{"1.5", "2.5", "-1.5", "1.7", "-1.2"},
+			[]int64{2, 3, -2, 2, -1}},
+		{fuzzy.RoundFloor,
+			[]string{"1.5", "2.5", "-1.5", "1.7", "-1.7"},
+			[]int64{1, 2, -2, 1, -2}},
+		{fuzzy.RoundCeil,
+			[]string{"1.5", "2.5", "-1.5", "1.7", "-1.7"},
+			[]int64{2, 3, -1, 2, -1}},
+		{fuzzy.RoundReject,
+			[]string{"1.0", "2e2", "-3"},
+			[]int64{1, 200, -3}},
+	} {
+		opts := fuzzy.Options{Rounding: tc.rounding}
+		for i, in := range tc.in {
+			d := Data{}
+			b := []byte(fmt.Sprintf(`{"int": %s}`, in))
+			err := fuzzy.UnmarshalWithOptions(b, &d, opts)
+			require.NoError(t, err)
+			require.Equal(t, tc.out[i], int64(d.Int), "rounding %v %s", tc.rounding, in)
+		}
+	}
+
+	d := Data{}
+	opts := fuzzy.Options{Rounding: fuzzy.RoundReject}
+	err := fuzzy.UnmarshalWithOptions([]byte(`{"int": 1.5}`), &d, opts)
+	require.ErrorIs(t, err, fuzzy.ErrFraction)
+
+	err = fuzzy.UnmarshalWithOptions([]byte(`{"int": 1e19}`), &d, fuzzy.Options{})
+	require.ErrorIs(t, err, fuzzy.ErrOutOfRange)
+}
+
+func TestOptionsBool(t *testing.T) {
+	type Data struct {
+		Bool  fuzzy.Bool      `json:"bool"`
+		Null  fuzzy.NullBool  `json:"null"`
+		Int   fuzzy.Int       `json:"int"`
+		Float fuzzy.NullFloat `json:"float"`
+	}
+
+	// Vocabulary
+	opts := fuzzy.Options{
+		BoolTrue:  []string{"y", "yes", "on"},
+		BoolFalse: []string{"n", "no", "off", ""},
+	}
+	d := Data{}
+	err := fuzzy.UnmarshalWithOptions(
+		[]byte(`{"bool": " Yes", "null": "N"}`), &d, opts)
+	require.NoError(t, err)
+	require.Equal(t, true, bool(d.Bool), "value must match")
+	require.Equal(t, true, d.Null.Valid, "bool must be valid")
+	require.Equal(t, false, d.Null.Bool, "value must match")
+
+	err = fuzzy.UnmarshalWithOptions([]byte(`{"bool": "true"}`), &d, opts)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidBoolString)
+
+	// Numbers are not affected
+	err = fuzzy.UnmarshalWithOptions([]byte(`{"bool": 0}`), &d, opts)
+	require.NoError(t, err)
+	require.Equal(t, false, bool(d.Bool), "value must match")
+
+	// Bool to number
+	err = fuzzy.UnmarshalWithOptions(
+		[]byte(`{"int": true, "float": false}`), &d, fuzzy.Options{})
+	require.ErrorIs(t, err, fuzzy.ErrBoolToNumber)
+	err = fuzzy.UnmarshalWithOptions(
+		[]byte(`{"int": true, "float": false}`), &d,
+		fuzzy.Options{BoolToNumber: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), int64(d.Int), "value must match")
+	require.Equal(t, true, d.Float.Valid, "float must be valid")
+	require.Equal(t, float64(0), d.Float.Float64, "value must match")
+}
+
+func TestOptionsEmptyString(t *testing.T) {
+	type Data struct {
+		String     fuzzy.String     `json:"string"`
+		Int        fuzzy.Int        `json:"int"`
+		NullString fuzzy.NullString `json:"nullString"`
+		NullInt    fuzzy.NullInt    `json:"nullInt"`
+	}
+	b := []byte(`{"string": " ", "int": "", "nullString": "", "nullInt": " "}`)
+
+	// Default
+	d := Data{}
+	err := fuzzy.UnmarshalWithOptions(b, &d, fuzzy.Options{})
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+
+	// Zero
+	d = Data{Int: 1}
+	err = fuzzy.UnmarshalWithOptions(b, &d, fuzzy.Options{
+		EmptyString: fuzzy.EmptyZero,
+		TrimSpace:   true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "", string(d.String), "value must match")
+	require.Equal(t, int64(0), int64(d.Int), "value must match")
+	require.Equal(t, true, d.NullString.Valid, "string must be valid")
+	require.Equal(t, true, d.NullInt.Valid, "int must be valid")
+	require.Equal(t, int64(0), d.NullInt.Int64, "value must match")
+
+	// Null
+	d = Data{}
+	err = fuzzy.UnmarshalWithOptions(b, &d, fuzzy.Options{
+		EmptyString: fuzzy.EmptyNull,
+		TrimSpace:   true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, false, d.NullString.Valid, "string must not be valid")
+	require.Equal(t, false, d.NullInt.Valid, "int must not be valid")
+
+	// Whitespace is kept without TrimSpace
+	err = fuzzy.UnmarshalWithOptions(b, &d, fuzzy.Options{
+		EmptyString: fuzzy.EmptyNull,
+	})
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	require.Equal(t, " ", string(d.String), "value must match")
+}
+
+// TestOptionsConcurrent decodes the same input with different options,
+// options must only apply to their own call
+func TestOptionsConcurrent(t *testing.T) {
+	type Data struct {
+		Int  fuzzy.Int  `json:"int"`
+		Bool fuzzy.Bool `json:"bool"`
+	}
+	b := []byte(`{"int": 2.5, "bool": "no"}`)
+	optsA := fuzzy.Options{Rounding: fuzzy.RoundCeil}
+	optsB := fuzzy.Options{
+		Rounding: fuzzy.RoundFloor, BoolFalse: []string{"no"}}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 200)
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			d := Data{}
+			if err := fuzzy.UnmarshalWithOptions(b, &d, optsA); err != nil {
+				errs <- err
+			} else if d.Int != 3 || d.Bool != true {
+				errs <- fmt.Errorf("options A: unexpected %+v", d)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			d := Data{}
+			if err := fuzzy.UnmarshalWithOptions(b, &d, optsB); err != nil {
+				errs <- err
+			} else if d.Int != 2 || d.Bool != false {
+				errs <- fmt.Errorf("options B: unexpected %+v", d)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	// UnmarshalJSON uses the defaults
+	d := Data{}
+	require.NoError(t, d.Int.UnmarshalJSON([]byte(`2.5`)))
+	require.Equal(t, fuzzy.Int(2), d.Int)
+}
+
+func TestDecoderSetOptions(t *testing.T) {
+	dec := fuzzy.NewDecoder(strings.NewReader(`{"qty": 1.5} {"qty": 1.5}`))
+	dec.Lenient()
+	dec.SetOptions(fuzzy.Options{Rounding: fuzzy.RoundReject})
+	require.False(t, dec.Options().Lenient, "options must be replaced")
+	o := Order{}
+	require.ErrorIs(t, dec.Decode(&o), fuzzy.ErrFraction)
+
+	opts := dec.Options()
+	opts.Lenient = true
+	dec.SetOptions(opts)
+	require.NoError(t, dec.Decode(&o))
+	require.Len(t, dec.Report(), 1)
+	require.ErrorIs(t, dec.Report()[0], fuzzy.ErrFraction)
+}
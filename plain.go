@@ -0,0 +1,201 @@
+package fuzzy
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+)
+
+// nullTypes maps guregu/null types to the fuzzy types with the same
+// underlying type, these are decoded with the fuzzy rules.
+// null.Time is decoded with nullTime, like NullTime
+var nullTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeFor[null.String](): reflect.TypeFor[NullString](),
+	reflect.TypeFor[null.Int]():    reflect.TypeFor[NullInt](),
+	reflect.TypeFor[null.Float]():  reflect.TypeFor[NullFloat](),
+	reflect.TypeFor[null.Bool]():   reflect.TypeFor[NullBool](),
+	reflect.TypeFor[null.Time]():   reflect.TypeFor[nullTime](),
+}
+
+var (
+	numberType   = reflect.TypeFor[json.Number]()
+	durationType = reflect.TypeFor[time.Duration]()
+)
+
+// fuzzyNull returns the fuzzy type to decode a guregu/null value with,
+// as interface I
+func fuzzyNull[I any](v reflect.Value) (i I, ok bool) {
+	t, ok := nullTypes[v.Type()]
+	if !ok || !v.CanAddr() {
+		return i, false
+	}
+	i, ok = v.Addr().Convert(reflect.PointerTo(t)).Interface().(I)
+	return i, ok
+}
+
+// isScalar returns true if v is a plain Go string, number or bool,
+// that is decoded with the fuzzy rules
+func isScalar(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return v.Type() != numberType
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isSQLNull returns true if t is one of the database/sql null types
+// with a scalar value, e.g. sql.NullInt64 or sql.Null[string]
+func isSQLNull(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.PkgPath() != "database/sql" ||
+		!strings.HasPrefix(t.Name(), "Null") || t.NumField() != 2 {
+		return false
+	}
+	valid := t.Field(1)
+	if valid.Name != "Valid" || valid.Type.Kind() != reflect.Bool {
+		return false
+	}
+	return isScalar(reflect.New(t.Field(0).Type).Elem())
+}
+
+// sqlNull decodes any JSON value to a database/sql null type,
+// null and empty strings with EmptyNull are not valid
+func (d *decodeState) sqlNull(bArr []byte, v reflect.Value) error {
+	valid, err := d.scalar(bArr, v.Field(0))
+	if err != nil {
+		return err
+	}
+	v.Field(1).SetBool(valid)
+	return nil
+}
+
+// scalar decodes any JSON value to a plain Go string, number or bool,
+// using the same rules as the fuzzy types.
+// Null sets v to the zero value, and valid is false
+func (d *decodeState) scalar(bArr []byte, v reflect.Value) (valid bool, err error) {
+	bArr = trim(bArr)
+	k, content, err := jsonValue(bArr, v.Addr().Interface())
+	if err != nil {
+		return false, err
+	}
+	return scalar(k, content, bArr, v, &d.opts)
+}
+
+// scalar coerces a value of kind k to a plain Go string, number or bool,
+// see coerce. Strings that are not numbers are decoded to time.Duration
+// like Duration, e.g. "5s", numbers are nanoseconds like encoding/json
+func scalar(k Kind, content, raw []byte, v reflect.Value, opts *Options) (
+	valid bool, err error) {
+
+	dst := v.Addr().Interface()
+	if v.Type() == durationType && k == KindString {
+		if numK, _ := textKind(content); numK != KindNumber {
+			d, valid, err := coerce[time.Duration](k, content, raw, dst, opts)
+			if err != nil {
+				return false, err
+			}
+			v.SetInt(int64(d))
+			return valid, nil
+		}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		s, valid, err := coerce[string](k, content, raw, dst, opts)
+		if err != nil {
+			return false, err
+		}
+		v.SetString(s)
+		return valid, nil
+
+	case reflect.Bool:
+		b, valid, err := coerce[bool](k, content, raw, dst, opts)
+		if err != nil {
+			return false, err
+		}
+		v.SetBool(b)
+		return valid, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, valid, err := coerce[int64](k, content, raw, dst, opts)
+		if err != nil {
+			return false, err
+		}
+		if v.OverflowInt(i) {
+			return false, outOfRange(k, raw, dst)
+		}
+		v.SetInt(i)
+		return valid, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		u, valid, err := coerceUint(k, content, raw, dst, opts)
+		if err != nil {
+			return false, err
+		}
+		if v.OverflowUint(u) {
+			return false, outOfRange(k, raw, dst)
+		}
+		v.SetUint(u)
+		return valid, nil
+
+	case reflect.Float32, reflect.Float64:
+		f, valid, err := coerce[float64](k, content, raw, dst, opts)
+		if err != nil {
+			return false, err
+		}
+		if v.OverflowFloat(f) {
+			return false, outOfRange(k, raw, dst)
+		}
+		v.SetFloat(f)
+		return valid, nil
+	}
+	return false, errors.Errorf("fuzzy: unsupported type %v", v.Type())
+}
+
+// coerceUint is like coerce[int64], but also decodes integers
+// beyond the range of int64. Negative numbers are out of range
+func coerceUint(k Kind, content, raw []byte, dst any, opts *Options) (
+	v uint64, valid bool, err error) {
+
+	i, valid, err := coerce[int64](k, content, raw, dst, opts)
+	if err == nil {
+		if i < 0 {
+			return 0, false, outOfRange(k, raw, dst)
+		}
+		return uint64(i), valid, nil
+	}
+	if !errors.Is(err, ErrOutOfRange) {
+		return 0, false, err
+	}
+
+	// Integers beyond int64
+	u, err := strconv.ParseUint(string(content), 10, 64)
+	if err != nil {
+		return 0, false, outOfRange(k, raw, dst)
+	}
+	return u, true, nil
+}
+
+// outOfRange error for the value of kind k
+func outOfRange(k Kind, raw []byte, dst any) error {
+	return withValue(coercionError(k, ErrOutOfRange, nil), dst, raw)
+}
+
+// typed sets the type of a CoercionError to t,
+// for values that are not fuzzy types
+func typed(err error, t reflect.Type) error {
+	var cErr *CoercionError
+	if errors.As(err, &cErr) {
+		cErr.Type = t.String()
+	}
+	return err
+}
@@ -0,0 +1,227 @@
+package fuzzy_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/mozey/fuzzy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalPlain(t *testing.T) {
+	type Item struct {
+		SKU string  `json:"sku"`
+		Qty uint16  `json:"qty"`
+		Tax float32 `json:"tax,omitempty"`
+	}
+	type Data struct {
+		String  string             `json:"string"`
+		Int     int                `json:"int"`
+		Int8    int8               `json:"int8"`
+		Int16   int16              `json:"int16"`
+		Int32   int32              `json:"int32"`
+		Int64   int64              `json:"int64"`
+		Uint    uint               `json:"uint"`
+		Uint8   uint8              `json:"uint8"`
+		Uint32  uint32             `json:"uint32"`
+		Uint64  uint64             `json:"uint64"`
+		Float32 float32            `json:"float32"`
+		Float64 float64            `json:"float64"`
+		Bool    bool               `json:"bool"`
+		Ptr     *int               `json:"ptr"`
+		Null    *int               `json:"null"`
+		Zero    int                `json:"zero"`
+		Slice   []int              `json:"slice"`
+		Map     map[string]float64 `json:"map"`
+		Items   []Item             `json:"items"`
+		Quoted  string             `json:"quoted,string"`
+		Number  json.Number        `json:"number"`
+		Bytes   []byte             `json:"bytes"`
+		Addr    netip.Addr         `json:"addr"`
+		Any     any                `json:"any"`
+	}
+	b := []byte(`{
+		"string": 123.40,
+		"int": "-1",
+		"int8": -128,
+		"int16": "32767",
+		"int32": 1.9,
+		"int64": "9223372036854775807",
+		"uint": "1",
+		"uint8": 255,
+		"uint32": 1e3,
+		"uint64": "18446744073709551615",
+		"float32": "1.5",
+		"float64": "-1e-3",
+		"bool": "1",
+		"ptr": "5",
+		"null": null,
+		"zero": null,
+		"slice": [1, "2", 3.5],
+		"map": {"a": "1.5", "b": 2},
+		"items": [{"sku": 12345, "qty": "2"}, {"sku": "A", "qty": 1.0, "tax": "0.15"}],
+		"quoted": "\"abc\"",
+		"number": 1.50,
+		"bytes": "AQI=",
+		"addr": "127.0.0.1",
+		"any": {"a": "1"}
+	}`)
+
+	d := Data{Zero: 1}
+	err := fuzzy.Unmarshal(b, &d)
+	require.NoError(t, err)
+	require.Equal(t, "123.40", d.String)
+	require.Equal(t, -1, d.Int)
+	require.Equal(t, int8(-128), d.Int8)
+	require.Equal(t, int16(32767), d.Int16)
+	require.Equal(t, int32(1), d.Int32)
+	require.Equal(t, int64(9223372036854775807), d.Int64)
+	require.Equal(t, uint(1), d.Uint)
+	require.Equal(t, uint8(255), d.Uint8)
+	require.Equal(t, uint32(1000), d.Uint32)
+	require.Equal(t, uint64(18446744073709551615), d.Uint64)
+	require.Equal(t, float32(1.5), d.Float32)
+	require.Equal(t, -1e-3, d.Float64)
+	require.Equal(t, true, d.Bool)
+	require.Equal(t, 5, *d.Ptr)
+	require.Nil(t, d.Null)
+	require.Equal(t, 0, d.Zero)
+	require.Equal(t, []int{1, 2, 3}, d.Slice)
+	require.Equal(t, map[string]float64{"a": 1.5, "b": 2}, d.Map)
+	require.Equal(t, []Item{
+		{SKU: "12345", Qty: 2},
+		{SKU: "A", Qty: 1, Tax: 0.15},
+	}, d.Items)
+	require.Equal(t, "abc", d.Quoted)
+	require.Equal(t, json.Number("1.50"), d.Number)
+	require.Equal(t, []byte{1, 2}, d.Bytes)
+	require.Equal(t, netip.MustParseAddr("127.0.0.1"), d.Addr)
+	require.Equal(t, map[string]any{"a": "1"}, d.Any)
+
+	// Field options apply to plain types
+	type Tagged struct {
+		Int   int    `json:"int" fuzzy:"round=half-up"`
+		Bool  bool   `json:"bool" fuzzy:"true=y,false=n"`
+		Empty *int64 `json:"empty" fuzzy:"zeroempty"`
+	}
+	tg := Tagged{}
+	err = fuzzy.Unmarshal([]byte(`{"int": 2.5, "bool": "Y", "empty": ""}`), &tg)
+	require.NoError(t, err)
+	require.Equal(t, 3, tg.Int)
+	require.Equal(t, true, tg.Bool)
+	require.Equal(t, int64(0), *tg.Empty)
+}
+
+func TestUnmarshalPlainErrors(t *testing.T) {
+	type Data struct {
+		Int8   int8    `json:"int8"`
+		Uint   uint    `json:"uint"`
+		Uint64 uint64  `json:"uint64"`
+		Float  float32 `json:"float"`
+		Int    int     `json:"int"`
+		Ints   []int   `json:"ints"`
+	}
+	for _, tc := range []struct {
+		b      string
+		reason error
+		typ    string
+		path   string
+	}{
+		{`{"int8": 128}`, fuzzy.ErrOutOfRange, "int8", "/int8"},
+		{`{"int8": "-129"}`, fuzzy.ErrOutOfRange, "int8", "/int8"},
+		{`{"uint": -1}`, fuzzy.ErrOutOfRange, "uint", "/uint"},
+		{`{"uint64": "18446744073709551616"}`, fuzzy.ErrOutOfRange, "uint64", "/uint64"},
+		{`{"float": 1e39}`, fuzzy.ErrOutOfRange, "float32", "/float"},
+		{`{"int": true}`, fuzzy.ErrBoolToNumber, "int", "/int"},
+		{`{"int": {}}`, fuzzy.ErrUnsupportedKind, "int", "/int"},
+		{`{"ints": [1, "x"]}`, fuzzy.ErrInvalidNumberString, "int", "/ints/1"},
+	} {
+		err := fuzzy.Unmarshal([]byte(tc.b), &Data{})
+		require.ErrorIs(t, err, tc.reason, tc.b)
+		var cErr *fuzzy.CoercionError
+		require.True(t, errors.As(err, &cErr), "err must be a CoercionError")
+		require.Equal(t, tc.typ, cErr.Type, tc.b)
+		require.Equal(t, tc.path, cErr.Path, tc.b)
+	}
+
+	// Lenient
+	d := Data{Int8: 1, Int: 1}
+	dec := fuzzy.NewDecoder(strings.NewReader(`{"int8": 128, "int": "x"}`))
+	dec.Lenient()
+	require.NoError(t, dec.Decode(&d))
+	require.Equal(t, int8(0), d.Int8)
+	require.Equal(t, 0, d.Int)
+	require.Len(t, dec.Report(), 2)
+}
+
+func TestUnmarshalNullTypes(t *testing.T) {
+	type Data struct {
+		String     null.String       `json:"string"`
+		Int        null.Int          `json:"int"`
+		Float      null.Float        `json:"float"`
+		Bool       null.Bool         `json:"bool"`
+		SQLString  sql.NullString    `json:"sqlString"`
+		SQLInt64   sql.NullInt64     `json:"sqlInt64"`
+		SQLInt32   sql.NullInt32     `json:"sqlInt32"`
+		SQLFloat64 sql.NullFloat64   `json:"sqlFloat64"`
+		SQLBool    sql.NullBool      `json:"sqlBool"`
+		SQLByte    sql.NullByte      `json:"sqlByte"`
+		SQLNull    sql.Null[float32] `json:"sqlNull"`
+		SQLNullPtr *sql.NullInt16    `json:"sqlNullPtr"`
+	}
+	b := []byte(`{
+		"string": 1,
+		"int": "2",
+		"float": "3.5",
+		"bool": "0",
+		"sqlString": true,
+		"sqlInt64": "4",
+		"sqlInt32": 5.0,
+		"sqlFloat64": "6.5",
+		"sqlBool": 1,
+		"sqlByte": "7",
+		"sqlNull": "8.5",
+		"sqlNullPtr": 9
+	}`)
+	d := Data{}
+	err := fuzzy.Unmarshal(b, &d)
+	require.NoError(t, err)
+	require.Equal(t, null.StringFrom("1"), d.String)
+	require.Equal(t, null.IntFrom(2), d.Int)
+	require.Equal(t, null.FloatFrom(3.5), d.Float)
+	require.Equal(t, null.BoolFrom(false), d.Bool)
+	require.Equal(t, sql.NullString{String: "true", Valid: true}, d.SQLString)
+	require.Equal(t, sql.NullInt64{Int64: 4, Valid: true}, d.SQLInt64)
+	require.Equal(t, sql.NullInt32{Int32: 5, Valid: true}, d.SQLInt32)
+	require.Equal(t, sql.NullFloat64{Float64: 6.5, Valid: true}, d.SQLFloat64)
+	require.Equal(t, sql.NullBool{Bool: true, Valid: true}, d.SQLBool)
+	require.Equal(t, sql.NullByte{Byte: 7, Valid: true}, d.SQLByte)
+	require.Equal(t, sql.Null[float32]{V: 8.5, Valid: true}, d.SQLNull)
+	require.Equal(t, &sql.NullInt16{Int16: 9, Valid: true}, d.SQLNullPtr)
+
+	// Null
+	err = fuzzy.Unmarshal([]byte(`{
+		"string": null, "int": null, "sqlString": null, "sqlInt64": null,
+		"sqlNull": null, "sqlNullPtr": null
+	}`), &d)
+	require.NoError(t, err)
+	require.False(t, d.String.Valid, "string must not be valid")
+	require.False(t, d.Int.Valid, "int must not be valid")
+	require.Equal(t, sql.NullString{}, d.SQLString)
+	require.Equal(t, sql.NullInt64{}, d.SQLInt64)
+	require.Equal(t, sql.Null[float32]{}, d.SQLNull)
+	require.Nil(t, d.SQLNullPtr)
+
+	// Errors name the type
+	err = fuzzy.Unmarshal([]byte(`{"int": true}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrBoolToNumber)
+	require.Contains(t, err.Error(), "to null.Int at /int")
+	err = fuzzy.Unmarshal([]byte(`{"sqlByte": 256}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrOutOfRange)
+	require.Contains(t, err.Error(), "to sql.NullByte at /sqlByte")
+}
@@ -0,0 +1,124 @@
+package fuzzy
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// ProblemContentType is the media type of Problem, see RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details document,
+// see NewProblem
+type Problem struct {
+	// Type is a URI that identifies the problem type,
+	// "about:blank" if it's empty
+	Type string `json:"type,omitempty"`
+	// Title is the status text, e.g. Bad Request
+	Title string `json:"title"`
+	// Status is the HTTP status code
+	Status int `json:"status"`
+	// Detail explains this occurrence of the problem
+	Detail string `json:"detail,omitempty"`
+	// Instance is a URI that identifies this occurrence of the problem
+	Instance string `json:"instance,omitempty"`
+	// InvalidParams lists the values that could not be decoded
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam is a value that could not be decoded
+type InvalidParam struct {
+	// Pointer is the JSON Pointer of the value, e.g. /orders/17/qty
+	Pointer string `json:"pointer"`
+	// Kind of the value received, e.g. bool
+	Kind string `json:"kind"`
+	// Type expected, e.g. fuzzy.Int
+	Type string `json:"type"`
+	// Reason the value could not be decoded
+	Reason string `json:"reason"`
+}
+
+// NewProblem returns the problem details for err,
+// e.g. an error returned by Unmarshal, Decoder.Decode or DecodeRequest.
+// Coercion errors, single or aggregated by CollectErrors,
+// are listed in InvalidParams with status 400 Bad Request.
+// The status of a RequestError is used, and JSON syntax errors are 400.
+// Other errors are 500 Internal Server Error, without details
+func NewProblem(err error) *Problem {
+	status := http.StatusInternalServerError
+	var errs Errors
+	var reqErr *RequestError
+	var cErr *CoercionError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &reqErr):
+		status = reqErr.Status
+		errs = reqErr.Errors()
+	case errors.As(err, &errs):
+		status = http.StatusBadRequest
+	case errors.As(err, &cErr):
+		status = http.StatusBadRequest
+		errs = Errors{cErr}
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		status = http.StatusBadRequest
+	}
+
+	p := &Problem{Title: http.StatusText(status), Status: status}
+	switch {
+	case len(errs) == 1:
+		p.Detail = "1 value could not be decoded"
+	case len(errs) > 1:
+		p.Detail = strconv.Itoa(len(errs)) + " values could not be decoded"
+	case status != http.StatusInternalServerError:
+		p.Detail = err.Error()
+	}
+	for _, e := range errs {
+		// Err may be nil for errors made by the caller
+		reason := e.Error()
+		if e.Err != nil {
+			reason = e.Err.Error()
+		}
+		p.InvalidParams = append(p.InvalidParams, InvalidParam{
+			Pointer: e.Path,
+			Kind:    e.Kind.String(),
+			Type:    e.Type,
+			Reason:  reason,
+		})
+	}
+	return p
+}
+
+// WriteProblem writes the problem details for err to w,
+// see NewProblem. The instance is the request path
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	p := NewProblem(err)
+	p.Instance = r.URL.Path
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// HandlerFunc is an http.Handler that returns an error,
+// the error is written with WriteProblem, e.g.
+//
+//	http.Handle("POST /orders", fuzzy.HandlerFunc(
+//		func(w http.ResponseWriter, r *http.Request) error {
+//			var o Order
+//			if err := fuzzy.DecodeRequest(r, &o, fuzzy.RequestOptions{}); err != nil {
+//				return err
+//			}
+//			...
+//		}))
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls h(w, r), and writes the error if there is one
+func (h HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h(w, r); err != nil {
+		WriteProblem(w, r, err)
+	}
+}
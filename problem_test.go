@@ -0,0 +1,114 @@
+package fuzzy_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mozey/fuzzy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProblem(t *testing.T) {
+	// Single error
+	err := fuzzy.Unmarshal([]byte(`{"qty": true}`), &Order{})
+	p := fuzzy.NewProblem(err)
+	require.Equal(t, &fuzzy.Problem{
+		Title:  "Bad Request",
+		Status: http.StatusBadRequest,
+		Detail: "1 value could not be decoded",
+		InvalidParams: []fuzzy.InvalidParam{{
+			Pointer: "/qty",
+			Kind:    "bool",
+			Type:    "fuzzy.Int",
+			Reason:  "bool can't be decoded to a number",
+		}},
+	}, p)
+
+	// Aggregated errors
+	err = fuzzy.UnmarshalWithOptions(
+		[]byte(`{"orders": [{"qty": "x"}, {"price": {}}]}`), &Orders{},
+		fuzzy.Options{CollectErrors: true})
+	p = fuzzy.NewProblem(errors.Wrap(err, "decode orders"))
+	require.Equal(t, http.StatusBadRequest, p.Status)
+	require.Equal(t, "2 values could not be decoded", p.Detail)
+	require.Equal(t, []fuzzy.InvalidParam{{
+		Pointer: "/orders/0/qty",
+		Kind:    "string",
+		Type:    "fuzzy.Int",
+		Reason:  "string is not a valid number",
+	}, {
+		Pointer: "/orders/1/price",
+		Kind:    "object",
+		Type:    "fuzzy.NullFloat",
+		Reason:  "unsupported kind",
+	}}, p.InvalidParams)
+
+	// Without a reason
+	p = fuzzy.NewProblem(&fuzzy.CoercionError{
+		Kind: fuzzy.KindString, Type: "fuzzy.Int", Path: "/qty"})
+	require.Equal(t, []fuzzy.InvalidParam{{
+		Pointer: "/qty",
+		Kind:    "string",
+		Type:    "fuzzy.Int",
+		Reason:  "fuzzy: cannot decode string to fuzzy.Int",
+	}}, p.InvalidParams)
+
+	// Syntax error
+	err = fuzzy.Unmarshal([]byte(`{"qty":`), &Order{})
+	p = fuzzy.NewProblem(err)
+	require.Equal(t, http.StatusBadRequest, p.Status)
+	require.Equal(t, "unexpected end of JSON input", p.Detail)
+	require.Empty(t, p.InvalidParams)
+
+	// Other errors
+	p = fuzzy.NewProblem(errors.New("db is down"))
+	require.Equal(t, &fuzzy.Problem{
+		Title:  "Internal Server Error",
+		Status: http.StatusInternalServerError,
+	}, p)
+}
+
+func TestHandlerFunc(t *testing.T) {
+	h := fuzzy.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var o OrderRequest
+		if err := fuzzy.DecodeRequest(r, &o, fuzzy.RequestOptions{}); err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/orders",
+		strings.NewReader(`{"qty": true}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, fuzzy.ProblemContentType, w.Header().Get("Content-Type"))
+	require.JSONEq(t, `{
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "1 value could not be decoded",
+		"instance": "/orders",
+		"invalid-params": [{
+			"pointer": "/qty",
+			"kind": "bool",
+			"type": "fuzzy.Int",
+			"reason": "bool can't be decoded to a number"
+		}]
+	}`, w.Body.String())
+
+	r = httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader("qty=1"))
+	r.Header.Set("Content-Type", "text/plain")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	r = httptest.NewRequest(http.MethodGet, "/orders?qty=1", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusNoContent, w.Code)
+}
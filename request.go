@@ -0,0 +1,189 @@
+package fuzzy
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxBodySize is the body size limit of DecodeRequest,
+// if RequestOptions.MaxBodySize is not set
+const DefaultMaxBodySize = 1 << 20
+
+// RequestOptions for DecodeRequest
+type RequestOptions struct {
+	// Options apply to all values decoded from the request
+	Options
+	// MaxBodySize is the max number of bytes read from the body,
+	// DefaultMaxBodySize is used if it's zero
+	MaxBodySize int64
+}
+
+// RequestError is returned by DecodeRequest if the request can't be decoded,
+// Status is the HTTP status code to respond with
+type RequestError struct {
+	// Status is one of
+	//	http.StatusBadRequest for values that can't be decoded
+	//	http.StatusRequestEntityTooLarge if the body is over the limit
+	//	http.StatusUnsupportedMediaType for other content types
+	Status int
+	// Err is the underlying error, e.g. a *CoercionError or Errors
+	Err error
+}
+
+// Error method for RequestError
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap method for RequestError
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// Errors returns the values that could not be decoded,
+// empty if the error is not a coercion error, e.g. a JSON syntax error
+func (e *RequestError) Errors() Errors {
+	var errs Errors
+	if errors.As(e.Err, &errs) {
+		return errs
+	}
+	var cErr *CoercionError
+	if errors.As(e.Err, &cErr) {
+		return Errors{cErr}
+	}
+	return nil
+}
+
+// DecodeRequest decodes an HTTP request to the struct pointed to by v.
+// The body is decoded depending on the Content-Type, with
+//   - Unmarshal for application/json, and types with the +json suffix
+//   - DecodeValues for application/x-www-form-urlencoded
+//     and multipart/form-data, files are ignored
+//
+// Bodies of GET and HEAD requests are ignored.
+// The query string and path values, see http.Request.PathValue,
+// are decoded with DecodeValues and merged,
+// path values take precedence over the body, and the body over the query.
+// Checkbox semantics only apply if the body is not JSON.
+//
+// Errors for the request are of type *RequestError
+func DecodeRequest(r *http.Request, v any, opts RequestOptions) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.Errorf("fuzzy: DecodeRequest needs a non-nil pointer, not %T", v)
+	}
+	maxBodySize := opts.MaxBodySize
+	if maxBodySize == 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+
+	query := r.URL.Query()
+	path := pathValues(r)
+
+	if !hasBody(r) {
+		return requestError(decodeValues(merge(query, path), v, opts.Options, true))
+	}
+
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return &RequestError{
+			Status: http.StatusUnsupportedMediaType, Err: errors.WithStack(err)}
+	}
+	r.Body = http.MaxBytesReader(nil, r.Body, maxBodySize)
+
+	switch {
+	case contentType == "application/json" ||
+		strings.HasSuffix(contentType, "+json"):
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			return requestError(errors.WithStack(err))
+		}
+		if err = decodeValues(query, v, opts.Options, false); err != nil {
+			return requestError(err)
+		}
+		if len(b) > 0 {
+			if err = UnmarshalWithOptions(b, v, opts.Options); err != nil {
+				return requestError(err)
+			}
+		}
+		return requestError(decodeValues(path, v, opts.Options, false))
+
+	case contentType == "application/x-www-form-urlencoded":
+		if err = r.ParseForm(); err != nil {
+			return requestError(errors.WithStack(err))
+		}
+		return requestError(
+			decodeValues(merge(query, r.PostForm, path), v, opts.Options, true))
+
+	case contentType == "multipart/form-data":
+		if err = r.ParseMultipartForm(maxBodySize); err != nil {
+			return requestError(errors.WithStack(err))
+		}
+		return requestError(decodeValues(
+			merge(query, r.MultipartForm.Value, path), v, opts.Options, true))
+	}
+	return &RequestError{
+		Status: http.StatusUnsupportedMediaType,
+		Err:    errors.Errorf("fuzzy: unsupported content type %q", contentType),
+	}
+}
+
+// hasBody returns true if the body of r must be decoded
+func hasBody(r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return false
+	}
+	return r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+}
+
+// pathValues of the wildcards in the pattern that matched r,
+// e.g. {id} in "GET /items/{id}"
+func pathValues(r *http.Request) url.Values {
+	values := url.Values{}
+	pattern := r.Pattern
+	for {
+		_, rest, ok := strings.Cut(pattern, "{")
+		if !ok {
+			return values
+		}
+		name, next, ok := strings.Cut(rest, "}")
+		if !ok {
+			return values
+		}
+		name = strings.TrimSuffix(name, "...")
+		if name != "$" {
+			values.Set(name, r.PathValue(name))
+		}
+		pattern = next
+	}
+}
+
+// merge values, keys of later values replace earlier ones
+func merge(values ...url.Values) url.Values {
+	merged := url.Values{}
+	for _, vs := range values {
+		for key, v := range vs {
+			merged[key] = v
+		}
+	}
+	return merged
+}
+
+// requestError wraps err in a RequestError with the status for err
+func requestError(err error) error {
+	if err == nil {
+		return nil
+	}
+	status := http.StatusBadRequest
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		status = http.StatusRequestEntityTooLarge
+	}
+	return &RequestError{Status: status, Err: err}
+}
@@ -0,0 +1,114 @@
+package fuzzy_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mozey/fuzzy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type OrderRequest struct {
+	ID      fuzzy.Int    `json:"id"`
+	Qty     fuzzy.Int    `json:"qty"`
+	Note    fuzzy.String `json:"note"`
+	Express fuzzy.Bool   `json:"express"`
+	Tags    []string     `json:"tags"`
+}
+
+// serve r with a handler for pattern that decodes the request
+func serve(t *testing.T, pattern string, r *http.Request, opts fuzzy.RequestOptions) (
+	OrderRequest, error) {
+
+	var o OrderRequest
+	var err error
+	mux := http.NewServeMux()
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		o = OrderRequest{Express: true}
+		err = fuzzy.DecodeRequest(r, &o, opts)
+	})
+	mux.ServeHTTP(httptest.NewRecorder(), r)
+	return o, err
+}
+
+func TestDecodeRequest(t *testing.T) {
+	// JSON body, path and query
+	r := httptest.NewRequest(http.MethodPost,
+		"/orders/7?note=query&qty=1&tags=a",
+		strings.NewReader(`{"id": 1, "qty": "2", "note": "body"}`))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	o, err := serve(t, "POST /orders/{id}", r, fuzzy.RequestOptions{})
+	require.NoError(t, err)
+	require.Equal(t, OrderRequest{
+		ID: 7, Qty: 2, Note: "body", Express: true, Tags: []string{"a"},
+	}, o, "bools must not be reset for JSON")
+
+	// Form body
+	r = httptest.NewRequest(http.MethodPost, "/orders/8?note=query&qty=1",
+		strings.NewReader("qty=3&tags=b&tags=c"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	o, err = serve(t, "POST /orders/{id}", r, fuzzy.RequestOptions{})
+	require.NoError(t, err)
+	require.Equal(t, OrderRequest{
+		ID: 8, Qty: 3, Note: "query", Tags: []string{"b", "c"},
+	}, o, "missing checkbox must be false")
+
+	// Multipart body
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	require.NoError(t, mw.WriteField("qty", "4"))
+	require.NoError(t, mw.WriteField("express", "on"))
+	require.NoError(t, mw.Close())
+	r = httptest.NewRequest(http.MethodPut, "/orders/9", body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	o, err = serve(t, "PUT /orders/{id...}", r, fuzzy.RequestOptions{})
+	require.NoError(t, err)
+	require.Equal(t, OrderRequest{ID: 9, Qty: 4, Express: true}, o)
+
+	// Query only
+	r = httptest.NewRequest(http.MethodGet, "/orders?qty=5&express=1", nil)
+	o, err = serve(t, "GET /orders", r, fuzzy.RequestOptions{})
+	require.NoError(t, err)
+	require.Equal(t, OrderRequest{Qty: 5, Express: true}, o)
+}
+
+func TestDecodeRequestErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		errs        int
+	}{
+		{"coercion", "application/json", `{"qty": true}`, http.StatusBadRequest, 1},
+		{"syntax", "application/json", `{"qty":`, http.StatusBadRequest, 0},
+		{"form", "application/x-www-form-urlencoded", "qty=x&id=y",
+			http.StatusBadRequest, 2},
+		{"too large", "application/json", `{"note": "` + strings.Repeat("x", 64) + `"}`,
+			http.StatusRequestEntityTooLarge, 0},
+		{"media type", "text/plain", "qty=1", http.StatusUnsupportedMediaType, 0},
+		{"no media type", "", "qty=1", http.StatusUnsupportedMediaType, 0},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tc.body))
+		r.Header.Set("Content-Type", tc.contentType)
+		_, err := serve(t, "POST /orders", r, fuzzy.RequestOptions{
+			Options:     fuzzy.Options{CollectErrors: true},
+			MaxBodySize: 32,
+		})
+		var reqErr *fuzzy.RequestError
+		require.True(t, errors.As(err, &reqErr), tc.name)
+		require.Equal(t, tc.status, reqErr.Status, tc.name)
+		require.Len(t, reqErr.Errors(), tc.errs, tc.name)
+	}
+
+	err := fuzzy.DecodeRequest(
+		httptest.NewRequest(http.MethodGet, "/", nil), OrderRequest{},
+		fuzzy.RequestOptions{})
+	require.EqualError(t, err,
+		"fuzzy: DecodeRequest needs a non-nil pointer, not fuzzy_test.OrderRequest")
+}
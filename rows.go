@@ -0,0 +1,190 @@
+package fuzzy
+
+import (
+	"database/sql"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	scannerType = reflect.TypeFor[sql.Scanner]()
+	timeType    = reflect.TypeFor[time.Time]()
+)
+
+// ScanRows scans all remaining rows and appends them to dst,
+// a pointer to a slice of structs or pointers to structs.
+// Columns are matched to fields by the db tag, or the field name,
+// compared case-insensitively. Columns without a field are skipped.
+// Column values are decoded with the same rules as JSON values,
+// for the fuzzy types and plain Go strings, numbers and bools,
+// and the fuzzy tag applies, e.g.
+//
+//	type Product struct {
+//		Qty    int64 `db:"qty"`
+//		Active bool  `db:"active" fuzzy:"true=Y,false=N"`
+//	}
+//	products := []Product{}
+//	err := fuzzy.ScanRows(rows, &products)
+//
+// A slice of any other type, e.g. []int64, is scanned from a single column
+func ScanRows(rows *sql.Rows, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() ||
+		v.Elem().Kind() != reflect.Slice {
+		return errors.Errorf("fuzzy: ScanRows needs a pointer to a slice, not %T", dst)
+	}
+	cols, err := rows.Columns()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	slice := v.Elem()
+	for rows.Next() {
+		e := reflect.New(slice.Type().Elem()).Elem()
+		if err = scanRow(rows, cols, e); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, e))
+	}
+	return errors.WithStack(rows.Err())
+}
+
+// ScanRow scans the current row into dst, a pointer to a struct,
+// see ScanRows. Call rows.Next before ScanRow,
+// sql.Row can't be used since it doesn't expose the columns
+func ScanRow(rows *sql.Rows, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return errors.Errorf("fuzzy: ScanRow needs a non-nil pointer, not %T", dst)
+	}
+	cols, err := rows.Columns()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return scanRow(rows, cols, v.Elem())
+}
+
+// scanRow scans the current row into v
+func scanRow(rows *sql.Rows, cols []string, v reflect.Value) error {
+	src := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range src {
+		ptrs[i] = &src[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return errors.WithStack(err)
+	}
+
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if !isRow(v) {
+		if len(cols) != 1 {
+			return errors.Errorf(
+				"fuzzy: %v can't be scanned from %d columns", v.Type(), len(cols))
+		}
+		return columnError(scanColumn(src[0], v, &defaultOptions), cols[0])
+	}
+
+	fields := cachedFields(v.Type(), "db")
+	for i, col := range cols {
+		f := lookupField(fields, col)
+		if f == nil {
+			continue
+		}
+		fv, err := f.value(v)
+		if err != nil {
+			return err
+		}
+		opts, err := f.options(defaultOptions)
+		if err != nil {
+			return err
+		}
+		if err := scanColumn(src[i], fv, &opts); err != nil {
+			return columnError(err, col)
+		}
+	}
+	return nil
+}
+
+// isRow returns true if v is a struct with a field per column,
+// and not a struct that is scanned from a single column, e.g. NullInt
+func isRow(v reflect.Value) bool {
+	return v.Kind() == reflect.Struct &&
+		!v.Addr().Type().Implements(scannerType) &&
+		!isSQLNull(v.Type()) && v.Type() != timeType
+}
+
+// scanColumn decodes the driver value src to v.
+// NULL sets pointers to nil
+func scanColumn(src any, v reflect.Value, opts *Options) error {
+	if v.Kind() == reflect.Pointer {
+		if src == nil {
+			v.SetZero()
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return scanColumn(src, v.Elem(), opts)
+	}
+
+	dst := v.Addr().Interface()
+	if s, ok := dst.(scanner); ok {
+		return s.scan(src, opts)
+	}
+	if s, ok := fuzzyNull[scanner](v); ok {
+		return typed(s.scan(src, opts), v.Type())
+	}
+
+	// Plain types are decoded with the same rules as the fuzzy types,
+	// the database/sql null types before their own Scan method
+	k, content, err := driverValue(src)
+	if err != nil {
+		return withValue(coercionError(k, ErrUnsupportedType, err), dst, nil)
+	}
+	if isSQLNull(v.Type()) {
+		valid, err := scalar(k, content, content, v.Field(0), opts)
+		if err != nil {
+			return typed(err, v.Type())
+		}
+		v.Field(1).SetBool(valid)
+		return nil
+	}
+	if s, ok := dst.(sql.Scanner); ok {
+		return errors.WithStack(s.Scan(src))
+	}
+	if isScalar(v) {
+		_, err = scalar(k, content, content, v, opts)
+		return err
+	}
+
+	// Other types, e.g. time.Time or []byte, must match the driver value
+	if src == nil {
+		v.SetZero()
+		return nil
+	}
+	if sv := reflect.ValueOf(src); sv.Type().AssignableTo(v.Type()) {
+		v.Set(sv)
+		return nil
+	}
+	return withValue(coercionError(k, ErrUnsupportedType, nil), dst, content)
+}
+
+// columnError sets the column on a CoercionError,
+// other errors are wrapped with the column name
+func columnError(err error, col string) error {
+	if err == nil {
+		return nil
+	}
+	var cErr *CoercionError
+	if errors.As(err, &cErr) {
+		cErr.Column = col
+		return err
+	}
+	return errors.Wrapf(err, "fuzzy: cannot scan column %s", col)
+}
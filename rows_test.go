@@ -0,0 +1,175 @@
+package fuzzy_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+// testConnector returns the same rows for every query
+type testConnector struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (c testConnector) Connect(context.Context) (driver.Conn, error) {
+	return testConn(c), nil
+}
+
+func (c testConnector) Driver() driver.Driver { return nil }
+
+type testConn testConnector
+
+func (c testConn) Prepare(string) (driver.Stmt, error) { return testStmt(c), nil }
+func (c testConn) Close() error                        { return nil }
+func (c testConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+type testStmt testConnector
+
+func (s testStmt) Close() error                               { return nil }
+func (s testStmt) NumInput() int                              { return -1 }
+func (s testStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (s testStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &testRows{cols: s.cols, rows: s.rows}, nil
+}
+
+type testRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (r *testRows) Columns() []string { return r.cols }
+func (r *testRows) Close() error      { return nil }
+func (r *testRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// query returns rows with the given columns and values
+func query(t *testing.T, cols []string, rows ...[]driver.Value) *sql.Rows {
+	db := sql.OpenDB(testConnector{cols: cols, rows: rows})
+	t.Cleanup(func() { _ = db.Close() })
+	r, err := db.Query("select")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = r.Close() })
+	return r
+}
+
+type Product struct {
+	ID      int64            `db:"id"`
+	SKU     string           `db:"sku"`
+	Qty     fuzzy.Int        `db:"qty"`
+	Price   fuzzy.NullFloat  `db:"price"`
+	Active  bool             `db:"active" fuzzy:"true=Y,false=N"`
+	Deleted fuzzy.Bool       `db:"deleted"`
+	Note    null.String      `db:"note"`
+	Stock   sql.NullInt32    `db:"stock"`
+	Weight  *float64         `db:"weight"`
+	Created time.Time        `db:"created"`
+	Tags    fuzzy.NullString // Matched by name
+	Ignored string           `db:"-"`
+}
+
+func TestScanRows(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cols := []string{"id", "sku", "qty", "price", "active", "deleted",
+		"note", "stock", "weight", "created", "TAGS", "extra"}
+	rows := query(t, cols,
+		[]driver.Value{int64(1), int64(12345), []byte("2"), "9.99", "Y",
+			int64(1), int64(7), []byte("3"), []byte("1.5"), created, "a,b", "x"},
+		[]driver.Value{[]byte("2"), "A", 3.0, nil, []byte("n"),
+			int64(0), nil, nil, nil, created, nil, nil},
+	)
+
+	products := []Product{}
+	err := fuzzy.ScanRows(rows, &products)
+	require.NoError(t, err)
+	weight := 1.5
+	require.Equal(t, []Product{{
+		ID: 1, SKU: "12345", Qty: 2,
+		Price:   fuzzy.NullFloat(null.FloatFrom(9.99)),
+		Active:  true,
+		Deleted: true,
+		Note:    null.StringFrom("7"),
+		Stock:   sql.NullInt32{Int32: 3, Valid: true},
+		Weight:  &weight,
+		Created: created,
+		Tags:    fuzzy.NullString(null.StringFrom("a,b")),
+	}, {
+		ID: 2, SKU: "A", Qty: 3,
+		Created: created,
+	}}, products)
+
+	// Single column
+	ids := []int64{}
+	err = fuzzy.ScanRows(query(t, []string{"id"},
+		[]driver.Value{"1"}, []driver.Value{int64(2)}), &ids)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, ids)
+
+	// Pointers to structs
+	ptrs := []*Product{}
+	err = fuzzy.ScanRows(query(t, []string{"id"}, []driver.Value{"3"}), &ptrs)
+	require.NoError(t, err)
+	require.Equal(t, []*Product{{ID: 3}}, ptrs)
+}
+
+func TestScanRow(t *testing.T) {
+	rows := query(t, []string{"id", "qty"}, []driver.Value{"1", "2.0"})
+	require.True(t, rows.Next(), "rows must have a row")
+	p := Product{}
+	err := fuzzy.ScanRow(rows, &p)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+
+	rows = query(t, []string{"id", "qty"}, []driver.Value{"1", int64(2)})
+	require.True(t, rows.Next(), "rows must have a row")
+	err = fuzzy.ScanRow(rows, &p)
+	require.NoError(t, err)
+	require.Equal(t, Product{ID: 1, Qty: 2}, p)
+}
+
+func TestScanRowsErrors(t *testing.T) {
+	for _, tc := range []struct {
+		col    string
+		value  driver.Value
+		reason error
+		msg    string
+	}{
+		{"qty", "abc", fuzzy.ErrInvalidNumberString,
+			`fuzzy: cannot decode string abc to fuzzy.Int in column qty: string is not a valid number: strconv.ParseInt: parsing "abc": invalid syntax`},
+		{"active", "x", fuzzy.ErrInvalidBoolString,
+			`fuzzy: cannot decode string x to bool in column active: string is not a valid bool`},
+		{"id", true, fuzzy.ErrBoolToNumber,
+			`fuzzy: cannot decode bool true to int64 in column id: bool can't be decoded to a number`},
+		{"stock", "2147483648", fuzzy.ErrOutOfRange,
+			`fuzzy: cannot decode string 2147483648 to sql.NullInt32 in column stock: number is out of range`},
+		{"created", "2024", fuzzy.ErrUnsupportedType,
+			`fuzzy: cannot decode string 2024 to time.Time in column created: unsupported type`},
+	} {
+		products := []Product{}
+		err := fuzzy.ScanRows(
+			query(t, []string{tc.col}, []driver.Value{tc.value}), &products)
+		require.ErrorIs(t, err, tc.reason, tc.col)
+		var cErr *fuzzy.CoercionError
+		require.ErrorAs(t, err, &cErr)
+		require.Equal(t, tc.col, cErr.Column)
+		require.Equal(t, tc.msg, err.Error())
+	}
+
+	err := fuzzy.ScanRows(query(t, []string{"id"}), Product{})
+	require.EqualError(t, err,
+		"fuzzy: ScanRows needs a pointer to a slice, not fuzzy_test.Product")
+	err = fuzzy.ScanRows(query(t, []string{"a", "b"}, []driver.Value{1, 2}), &[]int{})
+	require.EqualError(t, err, "fuzzy: int can't be scanned from 2 columns")
+}
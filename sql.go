@@ -0,0 +1,259 @@
+package fuzzy
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+)
+
+// scanner is implemented by the fuzzy types,
+// Scan calls scan with the default options
+type scanner interface {
+	scan(src any, opts *Options) error
+}
+
+// driverValue returns the kind and content of a database/sql driver value,
+// to be decoded with the same rules as JSON values, see goValue
+func driverValue(src any) (k Kind, content []byte, err error) {
+	if !driver.IsValue(src) {
+		return KindInvalid, nil, errors.Errorf("%T is not a driver value", src)
+	}
+	return goValue(src)
+}
+
+// valueOf T as a driver value,
+// dates, durations, decimals and big integers are strings
+// formatted like encodeText
+func valueOf[T Scalar](v T) driver.Value {
+	switch any(v).(type) {
+	case Date, time.Duration, Decimal, BigInt:
+		return string(encodeText(v))
+	}
+	return v
+}
+
+// scan any database/sql driver value to T.
+// Valid is false if the value is NULL
+func scan[T Scalar](src any, dst any, opts *Options) (v T, valid bool, err error) {
+	if v, ok := timeValue[T](src); ok {
+		return v, true, nil
+	}
+	k, content, err := driverValue(src)
+	if err != nil {
+		return v, false, withValue(
+			coercionError(k, ErrUnsupportedType, err), dst, nil)
+	}
+	return coerce[T](k, content, content, dst, opts)
+}
+
+// Scan implements the sql.Scanner interface for String
+func (fs *String) Scan(src any) error {
+	return fs.scan(src, &defaultOptions)
+}
+
+func (fs *String) scan(src any, opts *Options) error {
+	v, _, err := scan[string](src, fs, opts)
+	if err != nil {
+		return err
+	}
+	*fs = String(v)
+	return nil
+}
+
+// Value implements the driver.Valuer interface for String
+func (fs String) Value() (driver.Value, error) {
+	return string(fs), nil
+}
+
+// Scan implements the sql.Scanner interface for Int
+func (fi *Int) Scan(src any) error {
+	return fi.scan(src, &defaultOptions)
+}
+
+func (fi *Int) scan(src any, opts *Options) error {
+	v, _, err := scan[int64](src, fi, opts)
+	if err != nil {
+		return err
+	}
+	*fi = Int(v)
+	return nil
+}
+
+// Value implements the driver.Valuer interface for Int
+func (fi Int) Value() (driver.Value, error) {
+	return int64(fi), nil
+}
+
+// Scan implements the sql.Scanner interface for Float
+func (fi *Float) Scan(src any) error {
+	return fi.scan(src, &defaultOptions)
+}
+
+func (fi *Float) scan(src any, opts *Options) error {
+	v, _, err := scan[float64](src, fi, opts)
+	if err != nil {
+		return err
+	}
+	*fi = Float(v)
+	return nil
+}
+
+// Value implements the driver.Valuer interface for Float
+func (fi Float) Value() (driver.Value, error) {
+	return float64(fi), nil
+}
+
+// Scan implements the sql.Scanner interface for Bool
+func (fb *Bool) Scan(src any) error {
+	return fb.scan(src, &defaultOptions)
+}
+
+func (fb *Bool) scan(src any, opts *Options) error {
+	v, _, err := scan[bool](src, fb, opts)
+	if err != nil {
+		return err
+	}
+	*fb = Bool(v)
+	return nil
+}
+
+// Value implements the driver.Valuer interface for Bool
+func (fb Bool) Value() (driver.Value, error) {
+	return bool(fb), nil
+}
+
+// Scan implements the sql.Scanner interface for NullString,
+// NULL is not valid
+func (fs *NullString) Scan(src any) error {
+	return fs.scan(src, &defaultOptions)
+}
+
+func (fs *NullString) scan(src any, opts *Options) error {
+	v, valid, err := scan[string](src, fs, opts)
+	if err != nil {
+		return err
+	}
+	*fs = NullString(null.NewString(v, valid))
+	return nil
+}
+
+// Value implements the driver.Valuer interface for NullString
+func (fs NullString) Value() (driver.Value, error) {
+	if !fs.Valid {
+		return nil, nil
+	}
+	return fs.String, nil
+}
+
+// Scan implements the sql.Scanner interface for NullInt,
+// NULL is not valid
+func (fi *NullInt) Scan(src any) error {
+	return fi.scan(src, &defaultOptions)
+}
+
+func (fi *NullInt) scan(src any, opts *Options) error {
+	v, valid, err := scan[int64](src, fi, opts)
+	if err != nil {
+		return err
+	}
+	*fi = NullInt(null.NewInt(v, valid))
+	return nil
+}
+
+// Value implements the driver.Valuer interface for NullInt
+func (fi NullInt) Value() (driver.Value, error) {
+	if !fi.Valid {
+		return nil, nil
+	}
+	return fi.Int64, nil
+}
+
+// Scan implements the sql.Scanner interface for NullFloat,
+// NULL is not valid
+func (fi *NullFloat) Scan(src any) error {
+	return fi.scan(src, &defaultOptions)
+}
+
+func (fi *NullFloat) scan(src any, opts *Options) error {
+	v, valid, err := scan[float64](src, fi, opts)
+	if err != nil {
+		return err
+	}
+	*fi = NullFloat(null.NewFloat(v, valid))
+	return nil
+}
+
+// Value implements the driver.Valuer interface for NullFloat
+func (fi NullFloat) Value() (driver.Value, error) {
+	if !fi.Valid {
+		return nil, nil
+	}
+	return fi.Float64, nil
+}
+
+// Scan implements the sql.Scanner interface for NullBool,
+// NULL is not valid
+func (fb *NullBool) Scan(src any) error {
+	return fb.scan(src, &defaultOptions)
+}
+
+func (fb *NullBool) scan(src any, opts *Options) error {
+	v, valid, err := scan[bool](src, fb, opts)
+	if err != nil {
+		return err
+	}
+	*fb = NullBool(null.NewBool(v, valid))
+	return nil
+}
+
+// Value implements the driver.Valuer interface for NullBool
+func (fb NullBool) Value() (driver.Value, error) {
+	if !fb.Valid {
+		return nil, nil
+	}
+	return fb.Bool, nil
+}
+
+// Scan implements the sql.Scanner interface for Value
+func (fv *Value[T]) Scan(src any) error {
+	return fv.scan(src, &defaultOptions)
+}
+
+func (fv *Value[T]) scan(src any, opts *Options) error {
+	v, _, err := scan[T](src, fv, opts)
+	if err != nil {
+		return err
+	}
+	fv.V = v
+	return nil
+}
+
+// Value implements the driver.Valuer interface for Value
+func (fv Value[T]) Value() (driver.Value, error) {
+	return valueOf(fv.V), nil
+}
+
+// Scan implements the sql.Scanner interface for Null,
+// NULL is not valid
+func (fn *Null[T]) Scan(src any) error {
+	return fn.scan(src, &defaultOptions)
+}
+
+func (fn *Null[T]) scan(src any, opts *Options) error {
+	v, valid, err := scan[T](src, fn, opts)
+	if err != nil {
+		return err
+	}
+	fn.V, fn.Valid = v, valid
+	return nil
+}
+
+// Value implements the driver.Valuer interface for Null
+func (fn Null[T]) Value() (driver.Value, error) {
+	if !fn.Valid {
+		return nil, nil
+	}
+	return valueOf(fn.V), nil
+}
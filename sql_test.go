@@ -0,0 +1,156 @@
+package fuzzy_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+// Interfaces must be implemented
+var (
+	_ sql.Scanner   = (*fuzzy.NullString)(nil)
+	_ driver.Valuer = fuzzy.NullString{}
+	_ sql.Scanner   = (*fuzzy.Null[int64])(nil)
+	_ driver.Valuer = fuzzy.Value[bool]{}
+)
+
+func TestScan(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var s fuzzy.String
+	for _, tc := range []struct {
+		src any
+		v   string
+	}{
+		{[]byte("abc"), "abc"},
+		{"abc", "abc"},
+		{int64(-1), "-1"},
+		{1.5, "1.5"},
+		{true, "true"},
+		{ts, "2024-01-02T03:04:05Z"},
+		{nil, ""},
+	} {
+		require.NoError(t, s.Scan(tc.src))
+		require.Equal(t, fuzzy.String(tc.v), s, "value must match")
+	}
+
+	var i fuzzy.Int
+	for _, tc := range []struct {
+		src any
+		v   int64
+	}{
+		{[]byte("123"), 123},
+		{"-4", -4},
+		{int64(5), 5},
+		{6.9, 6},
+		{nil, 0},
+	} {
+		require.NoError(t, i.Scan(tc.src))
+		require.Equal(t, fuzzy.Int(tc.v), i, "value must match")
+	}
+
+	var f fuzzy.Float
+	require.NoError(t, f.Scan([]byte("1.25")))
+	require.Equal(t, fuzzy.Float(1.25), f, "value must match")
+	require.NoError(t, f.Scan(int64(2)))
+	require.Equal(t, fuzzy.Float(2), f, "value must match")
+
+	var b fuzzy.Bool
+	for _, tc := range []struct {
+		src any
+		v   bool
+	}{
+		{[]byte("1"), true},
+		{"false", false},
+		{int64(1), true},
+		{int64(0), false},
+		{0.5, true},
+		{true, true},
+		{nil, false},
+	} {
+		require.NoError(t, b.Scan(tc.src))
+		require.Equal(t, fuzzy.Bool(tc.v), b, "value must match")
+	}
+
+	// Errors
+	err := i.Scan("abc")
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	require.Equal(t,
+		`fuzzy: cannot decode string abc to fuzzy.Int: string is not a valid number: strconv.ParseInt: parsing "abc": invalid syntax`,
+		err.Error())
+	require.ErrorIs(t, i.Scan(true), fuzzy.ErrBoolToNumber)
+	require.ErrorIs(t, i.Scan(ts), fuzzy.ErrInvalidNumberString)
+	require.ErrorIs(t, i.Scan(struct{}{}), fuzzy.ErrUnsupportedType)
+}
+
+func TestScanNull(t *testing.T) {
+	var ns fuzzy.NullString
+	require.NoError(t, ns.Scan(int64(1)))
+	require.Equal(t, "1", ns.String)
+	require.True(t, ns.Valid, "value must be valid")
+	require.NoError(t, ns.Scan(nil))
+	require.False(t, ns.Valid, "value must not be valid")
+
+	var ni fuzzy.NullInt
+	require.NoError(t, ni.Scan([]byte("2")))
+	require.Equal(t, int64(2), ni.Int64)
+	require.True(t, ni.Valid, "value must be valid")
+	require.NoError(t, ni.Scan(nil))
+	require.False(t, ni.Valid, "value must not be valid")
+
+	var nf fuzzy.NullFloat
+	require.NoError(t, nf.Scan("3.5"))
+	require.Equal(t, 3.5, nf.Float64)
+	require.NoError(t, nf.Scan(nil))
+	require.False(t, nf.Valid, "value must not be valid")
+
+	var nb fuzzy.NullBool
+	require.NoError(t, nb.Scan(int64(1)))
+	require.True(t, nb.Bool, "value must be true")
+	require.NoError(t, nb.Scan(nil))
+	require.False(t, nb.Valid, "value must not be valid")
+
+	var n fuzzy.Null[int64]
+	require.NoError(t, n.Scan("7"))
+	require.Equal(t, fuzzy.Null[int64]{V: 7, Valid: true}, n)
+	require.NoError(t, n.Scan(nil))
+	require.Equal(t, fuzzy.Null[int64]{}, n)
+
+	var v fuzzy.Value[float64]
+	require.NoError(t, v.Scan([]byte("8.5")))
+	require.Equal(t, 8.5, v.V)
+}
+
+func TestValuer(t *testing.T) {
+	for _, tc := range []struct {
+		v        driver.Valuer
+		expected driver.Value
+	}{
+		{fuzzy.String("a"), "a"},
+		{fuzzy.Int(1), int64(1)},
+		{fuzzy.Float(1.5), 1.5},
+		{fuzzy.Bool(true), true},
+		{fuzzy.NullString{}, nil},
+		{fuzzy.NullInt{}, nil},
+		{fuzzy.NullFloat{}, nil},
+		{fuzzy.NullBool{}, nil},
+		{fuzzy.Value[int64]{V: 2}, int64(2)},
+		{fuzzy.Null[string]{}, nil},
+		{fuzzy.Null[string]{V: "b", Valid: true}, "b"},
+	} {
+		value, err := tc.v.Value()
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, value, "value must match")
+		require.True(t, driver.IsValue(value), "value must be a driver value")
+	}
+
+	var ni fuzzy.NullInt
+	require.NoError(t, ni.Scan(int64(3)))
+	value, err := ni.Value()
+	require.NoError(t, err)
+	require.Equal(t, int64(3), value)
+}
@@ -0,0 +1,198 @@
+package fuzzy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// fieldOptions are parsed from the fuzzy struct tag,
+// and layered over the Options of the call, e.g.
+//
+//	Qty  fuzzy.Int        `json:"qty" fuzzy:"round=half-even"`
+//	Name fuzzy.NullString `json:"name" fuzzy:"trim,nullempty"`
+//	Flag fuzzy.Bool       `json:"flag" fuzzy:"true=y|yes,false=n|no"`
+//
+// The tag is a comma separated list of
+//
+//	round=truncate|half-even|half-up|floor|ceil|reject
+//	trim         Options.TrimSpace
+//	nullempty    Options.EmptyString is EmptyNull
+//	zeroempty    Options.EmptyString is EmptyZero
+//	booltonumber Options.BoolToNumber
+//	lenient      Options.Lenient
+//	strict       Options.Lenient is false
+//	true=a|b     Options.BoolTrue
+//	false=a|b    Options.BoolFalse
+//	layout=a|b   Options.TimeLayouts, layouts must not contain , or |
+//	epoch=ms     Options.EpochUnit, one of auto, s, ms, us or ns
+//	date=dmy|mdy Options.DateOrder
+//	unit=ms      Options.DurationUnit, e.g. ns, ms, s, m or 1h
+//	scale=2      Options.MaxScale
+//	precision=12 Options.MaxPrecision
+type fieldOptions struct {
+	rounding     *Rounding
+	emptyString  *EmptyString
+	lenient      *bool
+	trimSpace    bool
+	boolToNumber bool
+	boolTrue     []string
+	boolFalse    []string
+	timeLayouts  []string
+	epochUnit    *EpochUnit
+	dateOrder    *DateOrder
+	durationUnit time.Duration
+	maxScale     int
+	maxPrecision int
+	// err is set if the tag is not valid
+	err error
+}
+
+var roundingNames = [...]string{
+	RoundTruncate: "truncate",
+	RoundHalfEven: "half-even",
+	RoundHalfUp:   "half-up",
+	RoundFloor:    "floor",
+	RoundCeil:     "ceil",
+	RoundReject:   "reject",
+}
+
+// String method for Rounding
+func (r Rounding) String() string {
+	if r < 0 || int(r) >= len(roundingNames) {
+		return fmt.Sprintf("Rounding(%d)", int(r))
+	}
+	return roundingNames[r]
+}
+
+// parseRounding is the inverse of Rounding.String
+func parseRounding(s string) (Rounding, error) {
+	for r, name := range roundingNames {
+		if s == name {
+			return Rounding(r), nil
+		}
+	}
+	return RoundTruncate, errors.Errorf("invalid rounding %q", s)
+}
+
+// parseFieldOptions from the fuzzy struct tag,
+// nil is returned if the tag is empty
+func parseFieldOptions(tag string) *fieldOptions {
+	if tag == "" {
+		return nil
+	}
+	fo := &fieldOptions{}
+	for _, opt := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch key {
+		case "round":
+			r, err := parseRounding(value)
+			if err != nil {
+				fo.err = err
+				return fo
+			}
+			fo.rounding = &r
+		case "trim":
+			fo.trimSpace = true
+		case "nullempty", "zeroempty":
+			e := EmptyNull
+			if key == "zeroempty" {
+				e = EmptyZero
+			}
+			fo.emptyString = &e
+		case "booltonumber":
+			fo.boolToNumber = true
+		case "lenient", "strict":
+			lenient := key == "lenient"
+			fo.lenient = &lenient
+		case "true":
+			fo.boolTrue = strings.Split(value, "|")
+		case "false":
+			fo.boolFalse = strings.Split(value, "|")
+		case "layout":
+			fo.timeLayouts = strings.Split(value, "|")
+		case "epoch":
+			u, err := parseEpochUnit(value)
+			if err != nil {
+				fo.err = err
+				return fo
+			}
+			fo.epochUnit = &u
+		case "date":
+			o, err := parseDateOrder(value)
+			if err != nil {
+				fo.err = err
+				return fo
+			}
+			fo.dateOrder = &o
+		case "unit":
+			u, err := parseDurationUnit(value)
+			if err != nil {
+				fo.err = err
+				return fo
+			}
+			fo.durationUnit = u
+		case "scale", "precision":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				fo.err = errors.Errorf("invalid %s %q", key, value)
+				return fo
+			}
+			if key == "scale" {
+				fo.maxScale = n
+			} else {
+				fo.maxPrecision = n
+			}
+		case "":
+		default:
+			fo.err = errors.Errorf("invalid option %q", key)
+			return fo
+		}
+	}
+	return fo
+}
+
+// apply the field options to opts
+func (fo *fieldOptions) apply(opts Options) Options {
+	if fo.rounding != nil {
+		opts.Rounding = *fo.rounding
+	}
+	if fo.emptyString != nil {
+		opts.EmptyString = *fo.emptyString
+	}
+	if fo.lenient != nil {
+		opts.Lenient = *fo.lenient
+	}
+	if fo.trimSpace {
+		opts.TrimSpace = true
+	}
+	if fo.boolToNumber {
+		opts.BoolToNumber = true
+	}
+	if fo.boolTrue != nil || fo.boolFalse != nil {
+		opts.BoolTrue = fo.boolTrue
+		opts.BoolFalse = fo.boolFalse
+	}
+	if fo.timeLayouts != nil {
+		opts.TimeLayouts = fo.timeLayouts
+	}
+	if fo.epochUnit != nil {
+		opts.EpochUnit = *fo.epochUnit
+	}
+	if fo.dateOrder != nil {
+		opts.DateOrder = *fo.dateOrder
+	}
+	if fo.durationUnit != 0 {
+		opts.DurationUnit = fo.durationUnit
+	}
+	if fo.maxScale != 0 {
+		opts.MaxScale = fo.maxScale
+	}
+	if fo.maxPrecision != 0 {
+		opts.MaxPrecision = fo.maxPrecision
+	}
+	return opts
+}
@@ -0,0 +1,139 @@
+package fuzzy_test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldOptions(t *testing.T) {
+	type Data struct {
+		Round     fuzzy.Int        `json:"round" fuzzy:"round=half-even"`
+		Reject    fuzzy.NullInt    `json:"reject" fuzzy:"round=reject"`
+		Truncate  fuzzy.Int        `json:"truncate"`
+		NullEmpty fuzzy.NullString `json:"nullEmpty" fuzzy:"trim,nullempty"`
+		ZeroEmpty fuzzy.NullFloat  `json:"zeroEmpty" fuzzy:"zeroempty"`
+		String    fuzzy.String     `json:"string" fuzzy:"trim"`
+		Bool      fuzzy.Bool       `json:"bool" fuzzy:"true=y|yes,false=n|no|"`
+		NullBool  fuzzy.NullBool   `json:"nullBool" fuzzy:"true=y,false=n"`
+		BoolFloat fuzzy.Float      `json:"boolFloat" fuzzy:"booltonumber"`
+		Lenient   fuzzy.NullInt    `json:"lenient" fuzzy:"lenient"`
+		NotTagged fuzzy.NullString `json:"notTagged"`
+		Nested    struct {
+			Int fuzzy.Int `json:"int"`
+		} `json:"nested" fuzzy:"round=ceil"`
+	}
+	b := []byte(`{
+		"round": 2.5,
+		"reject": 3.0,
+		"truncate": 2.5,
+		"nullEmpty": "  ",
+		"zeroEmpty": "",
+		"string": " a ",
+		"bool": "Yes",
+		"nullBool": "n",
+		"boolFloat": true,
+		"lenient": "abc",
+		"notTagged": "",
+		"nested": {"int": 1.1}
+	}`)
+
+	d := Data{}
+	err := fuzzy.Unmarshal(b, &d)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), int64(d.Round), "value must match")
+	require.Equal(t, true, d.Reject.Valid, "int must be valid")
+	require.Equal(t, int64(3), d.Reject.Int64, "value must match")
+	require.Equal(t, int64(2), int64(d.Truncate), "value must match")
+	require.Equal(t, false, d.NullEmpty.Valid, "string must not be valid")
+	require.Equal(t, true, d.ZeroEmpty.Valid, "float must be valid")
+	require.Equal(t, float64(0), d.ZeroEmpty.Float64, "value must match")
+	require.Equal(t, "a", string(d.String), "value must match")
+	require.Equal(t, true, bool(d.Bool), "value must match")
+	require.Equal(t, true, d.NullBool.Valid, "bool must be valid")
+	require.Equal(t, false, d.NullBool.Bool, "value must match")
+	require.Equal(t, float64(1), float64(d.BoolFloat), "value must match")
+	require.Equal(t, false, d.Lenient.Valid, "int must not be valid")
+	require.Equal(t, true, d.NotTagged.Valid, "string must be valid")
+	require.Equal(t, int64(2), int64(d.Nested.Int), "value must match")
+
+	// encoding/json ignores the fuzzy tag
+	d = Data{}
+	err = json.Unmarshal([]byte(`{"round": 2.5, "nullEmpty": ""}`), &d)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), int64(d.Round), "value must match")
+	require.Equal(t, true, d.NullEmpty.Valid, "string must be valid")
+
+	// Field options are layered over the call options
+	opts := fuzzy.Options{Rounding: fuzzy.RoundCeil, EmptyString: fuzzy.EmptyNull}
+	d = Data{}
+	err = fuzzy.UnmarshalWithOptions(
+		[]byte(`{"round": 2.5, "truncate": 2.5, "notTagged": ""}`), &d, opts)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), int64(d.Round), "value must match")
+	require.Equal(t, int64(3), int64(d.Truncate), "value must match")
+	require.Equal(t, false, d.NotTagged.Valid, "string must not be valid")
+
+	// Strict fields are not lenient
+	type Strict struct {
+		Int    fuzzy.Int `json:"int" fuzzy:"strict"`
+		Other  fuzzy.Int `json:"other"`
+		Reject fuzzy.Int `json:"reject" fuzzy:"round=reject"`
+	}
+	s := Strict{}
+	err = fuzzy.UnmarshalWithOptions(
+		[]byte(`{"other": "x", "reject": 1.5}`), &s, fuzzy.Options{Lenient: true})
+	require.NoError(t, err)
+	err = fuzzy.UnmarshalWithOptions(
+		[]byte(`{"int": "x"}`), &s, fuzzy.Options{Lenient: true})
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+
+	err = fuzzy.Unmarshal([]byte(`{"reject": 1.5}`), &s)
+	require.ErrorIs(t, err, fuzzy.ErrFraction)
+}
+
+func TestFieldOptionsInvalid(t *testing.T) {
+	type Data struct {
+		Int fuzzy.Int `json:"int" fuzzy:"round=up"`
+	}
+	err := fuzzy.Unmarshal([]byte(`{"int": 1}`), &Data{})
+	require.EqualError(t, err,
+		`fuzzy: invalid tag on field int of fuzzy_test.Data: invalid rounding "up"`)
+
+	type Data2 struct {
+		Int fuzzy.Int `json:"int" fuzzy:"foo"`
+	}
+	err = fuzzy.Unmarshal([]byte(`{"int": 1}`), &Data2{})
+	require.EqualError(t, err,
+		`fuzzy: invalid tag on field int of fuzzy_test.Data2: invalid option "foo"`)
+}
+
+func TestFieldOptionsInvalidDecoders(t *testing.T) {
+	type Bad struct {
+		Int fuzzy.Int `fuzzy:"foo"`
+	}
+	want := `fuzzy: invalid tag on field Int of fuzzy_test.Bad: invalid option "foo"`
+
+	err := fuzzy.Unmarshal([]byte(`{"Int": 1}`), &Bad{})
+	require.EqualError(t, err, want)
+	err = fuzzy.DecodeValues(url.Values{"Int": {"1"}}, &Bad{})
+	require.EqualError(t, err, want)
+	err = fuzzy.DecodeEnvMap(map[string]string{"INT": "1"}, &Bad{}, "")
+	require.EqualError(t, err, want)
+	err = fuzzy.NewCSVDecoder(csv.NewReader(strings.NewReader("Int\n1\n"))).Decode(&Bad{})
+	require.ErrorContains(t, err, want)
+	err = fuzzy.FlagSet(flag.NewFlagSet("test", flag.ContinueOnError), &Bad{})
+	require.EqualError(t, err, want)
+}
+
+func TestRoundingString(t *testing.T) {
+	require.Equal(t, "half-even", fuzzy.RoundHalfEven.String())
+	require.Equal(t, "reject", fuzzy.RoundReject.String())
+	require.Equal(t, "Rounding(99)", fuzzy.Rounding(99).String())
+}
@@ -0,0 +1,332 @@
+package fuzzy
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/guregu/null"
+)
+
+// textUnmarshaler is implemented by the fuzzy types,
+// UnmarshalText calls unmarshalText with the default options
+type textUnmarshaler interface {
+	unmarshalText(k Kind, text []byte, opts *Options) error
+}
+
+// decodeText decodes text of kind k to T, see coerce.
+// Text is a string unless it's sniffed with textKind
+func decodeText[T Scalar](k Kind, text []byte, dst any, opts *Options) (
+	v T, valid bool, err error) {
+
+	return coerce[T](k, text, text, dst, opts)
+}
+
+// textKind returns KindNumber and the trimmed text if text is
+// a valid JSON number, e.g. 12.0, otherwise KindString and text as is
+func textKind(text []byte) (Kind, []byte) {
+	trimmed := bytes.TrimSpace(text)
+	if kindOf(trimmed) == KindNumber && json.Valid(trimmed) {
+		return KindNumber, trimmed
+	}
+	return KindString, text
+}
+
+// nullTextKind of text decoded by UnmarshalText of the Null types,
+// empty text is null
+func nullTextKind(text []byte) Kind {
+	if len(text) == 0 {
+		return KindNull
+	}
+	return KindString
+}
+
+// encodeText encodes T as text,
+// numbers, bools, times and durations are formatted like encode
+// with the default options
+func encodeText[T Scalar](v T) []byte {
+	switch v := any(v).(type) {
+	case string:
+		return []byte(v)
+	case int64:
+		return strconv.AppendInt(nil, v, 10)
+	case float64:
+		return strconv.AppendFloat(nil, v, 'f', -1, 64)
+	case bool:
+		return strconv.AppendBool(nil, v)
+	case time.Time:
+		text, _ := formatTime(v, &defaultOptions)
+		return text
+	case Date:
+		return []byte(v.String())
+	case time.Duration:
+		text, _ := formatDuration(v, &defaultOptions)
+		return text
+	case Decimal:
+		return []byte(v.String())
+	case BigInt:
+		return []byte(v.String())
+	}
+	return nil
+}
+
+// formatText formats v as text, numbers and bools are formatted like
+// encodeText, and types that implement encoding.TextMarshaler format themselves.
+// Nil pointers and invalid database/sql null types are empty
+func formatText(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+	if v.CanAddr() {
+		if m, ok := v.Addr().Interface().(encoding.TextMarshaler); ok {
+			text, err := m.MarshalText()
+			return string(text), err
+		}
+	}
+	if isSQLNull(v.Type()) {
+		if !v.Field(1).Bool() {
+			return "", nil
+		}
+		return formatText(v.Field(0))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// MarshalText method for String
+func (fs String) MarshalText() ([]byte, error) {
+	return encodeText(string(fs)), nil
+}
+
+// UnmarshalText method for String
+func (fs *String) UnmarshalText(text []byte) error {
+	return fs.unmarshalText(KindString, text, &defaultOptions)
+}
+
+func (fs *String) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, _, err := decodeText[string](k, text, fs, opts)
+	if err != nil {
+		return err
+	}
+	*fs = String(v)
+	return nil
+}
+
+// MarshalText method for Int
+func (fi Int) MarshalText() ([]byte, error) {
+	return encodeText(int64(fi)), nil
+}
+
+// UnmarshalText method for Int
+func (fi *Int) UnmarshalText(text []byte) error {
+	return fi.unmarshalText(KindString, text, &defaultOptions)
+}
+
+func (fi *Int) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, _, err := decodeText[int64](k, text, fi, opts)
+	if err != nil {
+		return err
+	}
+	*fi = Int(v)
+	return nil
+}
+
+// MarshalText method for Float
+func (fi Float) MarshalText() ([]byte, error) {
+	return encodeText(float64(fi)), nil
+}
+
+// UnmarshalText method for Float
+func (fi *Float) UnmarshalText(text []byte) error {
+	return fi.unmarshalText(KindString, text, &defaultOptions)
+}
+
+func (fi *Float) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, _, err := decodeText[float64](k, text, fi, opts)
+	if err != nil {
+		return err
+	}
+	*fi = Float(v)
+	return nil
+}
+
+// MarshalText method for Bool
+func (fb Bool) MarshalText() ([]byte, error) {
+	return encodeText(bool(fb)), nil
+}
+
+// UnmarshalText method for Bool
+func (fb *Bool) UnmarshalText(text []byte) error {
+	return fb.unmarshalText(KindString, text, &defaultOptions)
+}
+
+func (fb *Bool) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, _, err := decodeText[bool](k, text, fb, opts)
+	if err != nil {
+		return err
+	}
+	*fb = Bool(v)
+	return nil
+}
+
+// MarshalText method for NullString,
+// invalid values are empty
+func (fs NullString) MarshalText() ([]byte, error) {
+	if !fs.Valid {
+		return []byte{}, nil
+	}
+	return encodeText(fs.String), nil
+}
+
+// UnmarshalText method for NullString,
+// empty text is not valid like MarshalText of invalid values
+func (fs *NullString) UnmarshalText(text []byte) error {
+	return fs.unmarshalText(nullTextKind(text), text, &defaultOptions)
+}
+
+func (fs *NullString) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, valid, err := decodeText[string](k, text, fs, opts)
+	if err != nil {
+		return err
+	}
+	*fs = NullString(null.NewString(v, valid))
+	return nil
+}
+
+// MarshalText method for NullInt,
+// invalid values are empty
+func (fi NullInt) MarshalText() ([]byte, error) {
+	if !fi.Valid {
+		return []byte{}, nil
+	}
+	return encodeText(fi.Int64), nil
+}
+
+// UnmarshalText method for NullInt,
+// empty text is not valid like MarshalText of invalid values
+func (fi *NullInt) UnmarshalText(text []byte) error {
+	return fi.unmarshalText(nullTextKind(text), text, &defaultOptions)
+}
+
+func (fi *NullInt) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, valid, err := decodeText[int64](k, text, fi, opts)
+	if err != nil {
+		return err
+	}
+	*fi = NullInt(null.NewInt(v, valid))
+	return nil
+}
+
+// MarshalText method for NullFloat,
+// invalid values are empty
+func (fi NullFloat) MarshalText() ([]byte, error) {
+	if !fi.Valid {
+		return []byte{}, nil
+	}
+	return encodeText(fi.Float64), nil
+}
+
+// UnmarshalText method for NullFloat,
+// empty text is not valid like MarshalText of invalid values
+func (fi *NullFloat) UnmarshalText(text []byte) error {
+	return fi.unmarshalText(nullTextKind(text), text, &defaultOptions)
+}
+
+func (fi *NullFloat) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, valid, err := decodeText[float64](k, text, fi, opts)
+	if err != nil {
+		return err
+	}
+	*fi = NullFloat(null.NewFloat(v, valid))
+	return nil
+}
+
+// MarshalText method for NullBool,
+// invalid values are empty
+func (fb NullBool) MarshalText() ([]byte, error) {
+	if !fb.Valid {
+		return []byte{}, nil
+	}
+	return encodeText(fb.Bool), nil
+}
+
+// UnmarshalText method for NullBool,
+// empty text is not valid like MarshalText of invalid values
+func (fb *NullBool) UnmarshalText(text []byte) error {
+	return fb.unmarshalText(nullTextKind(text), text, &defaultOptions)
+}
+
+func (fb *NullBool) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, valid, err := decodeText[bool](k, text, fb, opts)
+	if err != nil {
+		return err
+	}
+	*fb = NullBool(null.NewBool(v, valid))
+	return nil
+}
+
+// MarshalText method for Value
+func (fv Value[T]) MarshalText() ([]byte, error) {
+	return encodeText(fv.V), nil
+}
+
+// UnmarshalText method for Value
+func (fv *Value[T]) UnmarshalText(text []byte) error {
+	return fv.unmarshalText(KindString, text, &defaultOptions)
+}
+
+func (fv *Value[T]) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, _, err := decodeText[T](k, text, fv, opts)
+	if err != nil {
+		return err
+	}
+	fv.V = v
+	return nil
+}
+
+// MarshalText method for Null,
+// invalid values are empty
+func (fn Null[T]) MarshalText() ([]byte, error) {
+	if !fn.Valid {
+		return []byte{}, nil
+	}
+	return encodeText(fn.V), nil
+}
+
+// UnmarshalText method for Null,
+// empty text is not valid like MarshalText of invalid values
+func (fn *Null[T]) UnmarshalText(text []byte) error {
+	return fn.unmarshalText(nullTextKind(text), text, &defaultOptions)
+}
+
+func (fn *Null[T]) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, valid, err := decodeText[T](k, text, fn, opts)
+	if err != nil {
+		return err
+	}
+	fn.V, fn.Valid = v, valid
+	return nil
+}
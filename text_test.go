@@ -0,0 +1,147 @@
+package fuzzy_test
+
+import (
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+// Interfaces must be implemented
+var (
+	_ encoding.TextMarshaler   = fuzzy.NullBool{}
+	_ encoding.TextUnmarshaler = (*fuzzy.NullBool)(nil)
+	_ encoding.TextMarshaler   = fuzzy.Null[float64]{}
+	_ encoding.TextUnmarshaler = (*fuzzy.Value[string])(nil)
+)
+
+func TestUnmarshalText(t *testing.T) {
+	var s fuzzy.String
+	require.NoError(t, s.UnmarshalText([]byte("abc")))
+	require.Equal(t, fuzzy.String("abc"), s)
+
+	var i fuzzy.Int
+	require.NoError(t, i.UnmarshalText([]byte("123")))
+	require.Equal(t, fuzzy.Int(123), i)
+	err := i.UnmarshalText([]byte("1.5"))
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	require.Equal(t, fuzzy.Int(123), i, "value must not change on error")
+
+	var f fuzzy.Float
+	require.NoError(t, f.UnmarshalText([]byte("1.5")))
+	require.Equal(t, fuzzy.Float(1.5), f)
+
+	var b fuzzy.Bool
+	require.NoError(t, b.UnmarshalText([]byte("1")))
+	require.Equal(t, fuzzy.Bool(true), b)
+	require.NoError(t, b.UnmarshalText([]byte("")))
+	require.Equal(t, fuzzy.Bool(false), b)
+
+	var ns fuzzy.NullString
+	require.NoError(t, ns.UnmarshalText([]byte("a")))
+	require.Equal(t, fuzzy.NullString(null.StringFrom("a")), ns)
+	require.NoError(t, ns.UnmarshalText([]byte("")))
+	require.Equal(t, fuzzy.NullString{}, ns, "empty text must not be valid")
+
+	var ni fuzzy.NullInt
+	require.NoError(t, ni.UnmarshalText([]byte("-1")))
+	require.Equal(t, fuzzy.NullInt(null.IntFrom(-1)), ni)
+	require.NoError(t, ni.UnmarshalText([]byte("")))
+	require.Equal(t, fuzzy.NullInt{}, ni, "empty text must not be valid")
+	require.ErrorIs(t, ni.UnmarshalText([]byte(" ")), fuzzy.ErrInvalidNumberString)
+
+	var nf fuzzy.NullFloat
+	require.NoError(t, nf.UnmarshalText([]byte("2.5")))
+	require.Equal(t, fuzzy.NullFloat(null.FloatFrom(2.5)), nf)
+
+	var nb fuzzy.NullBool
+	require.NoError(t, nb.UnmarshalText([]byte("false")))
+	require.Equal(t, fuzzy.NullBool(null.BoolFrom(false)), nb)
+
+	var n fuzzy.Null[int64]
+	require.NoError(t, n.UnmarshalText([]byte("3")))
+	require.Equal(t, fuzzy.Null[int64]{V: 3, Valid: true}, n)
+}
+
+func TestMarshalText(t *testing.T) {
+	for _, tc := range []struct {
+		v        encoding.TextMarshaler
+		expected string
+	}{
+		{fuzzy.String("a b"), "a b"},
+		{fuzzy.Int(-1), "-1"},
+		{fuzzy.Float(1.5), "1.5"},
+		{fuzzy.Float(1e21), "1000000000000000000000"},
+		{fuzzy.Bool(true), "true"},
+		{fuzzy.NullString{}, ""},
+		{fuzzy.NullInt(null.IntFrom(2)), "2"},
+		{fuzzy.NullFloat{}, ""},
+		{fuzzy.NullBool(null.BoolFrom(false)), "false"},
+		{fuzzy.Value[int64]{V: 3}, "3"},
+		{fuzzy.Null[bool]{}, ""},
+	} {
+		text, err := tc.v.MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, string(text), "value must match")
+	}
+}
+
+func TestNullTextRoundTrip(t *testing.T) {
+	for _, v := range []interface {
+		encoding.TextMarshaler
+		encoding.TextUnmarshaler
+	}{
+		&fuzzy.NullString{}, &fuzzy.NullInt{}, &fuzzy.NullFloat{}, &fuzzy.NullBool{},
+		&fuzzy.NullTime{}, &fuzzy.NullDate{}, &fuzzy.NullDuration{},
+		&fuzzy.NullDecimal{}, &fuzzy.NullBigInt{}, &fuzzy.Null[int64]{},
+	} {
+		// Invalid values are empty text, and empty text is not valid
+		text, err := v.MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, "", string(text), "text must be empty for %T", v)
+		require.NoError(t, v.UnmarshalText(text), "%T", v)
+		again, err := v.MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, "", string(again), "text must be empty for %T", v)
+	}
+
+	// flag.TextVar
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var limit fuzzy.NullInt
+	fs.TextVar(&limit, "limit", fuzzy.NullInt{}, "max number of items")
+	require.NoError(t, fs.Parse([]string{"-limit", "5"}))
+	require.Equal(t, fuzzy.NullInt(null.IntFrom(5)), limit)
+}
+
+func TestTextLibraries(t *testing.T) {
+	// Map keys in encoding/json
+	m := map[fuzzy.Int]fuzzy.String{}
+	err := json.Unmarshal([]byte(`{"1": "a", "2": 3}`), &m)
+	require.NoError(t, err)
+	require.Equal(t, map[fuzzy.Int]fuzzy.String{1: "a", 2: "3"}, m)
+	b, err := json.Marshal(map[fuzzy.Float]int{1.5: 1})
+	require.NoError(t, err)
+	require.Equal(t, `{"1.5":1}`, string(b))
+
+	// Attributes in encoding/xml
+	type Item struct {
+		Qty    fuzzy.Int      `xml:"qty,attr"`
+		Active fuzzy.NullBool `xml:"active,attr"`
+	}
+	item := Item{}
+	err = xml.Unmarshal([]byte(`<item qty="2" active="0"/>`), &item)
+	require.NoError(t, err)
+	require.Equal(t, Item{Qty: 2, Active: fuzzy.NullBool(null.BoolFrom(false))}, item)
+
+	// flag.TextVar
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var qty fuzzy.Int
+	fs.TextVar(&qty, "qty", fuzzy.Int(1), "quantity")
+	require.NoError(t, fs.Parse([]string{"-qty", "5"}))
+	require.Equal(t, fuzzy.Int(5), qty)
+}
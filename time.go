@@ -0,0 +1,268 @@
+package fuzzy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+)
+
+// defaultTimeLayouts are tried in order to decode strings to Time,
+// if Options.TimeLayouts is empty.
+// Fractional seconds are optional, and layouts without an offset are UTC.
+// Must not be modified
+var defaultTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// DefaultTimeLayouts returns a copy of the layouts tried in order to
+// decode strings to Time, if Options.TimeLayouts is empty
+func DefaultTimeLayouts() []string {
+	return slices.Clone(defaultTimeLayouts)
+}
+
+// Formats for Options.TimeFormat that encode Unix epochs
+const (
+	TimeFormatUnix      = "unix"
+	TimeFormatUnixMilli = "unixmilli"
+	TimeFormatUnixMicro = "unixmicro"
+	TimeFormatUnixNano  = "unixnano"
+)
+
+// EpochUnit of numbers decoded to Time
+type EpochUnit int
+
+const (
+	// EpochAuto detects the unit by the magnitude of the number,
+	// less than 1e11 is seconds, 1e14 is milliseconds,
+	// 1e17 is microseconds, and nanoseconds otherwise.
+	// Dates between 1973 and 5138 are decoded from any unit
+	EpochAuto EpochUnit = iota
+	// EpochSeconds since January 1, 1970 UTC
+	EpochSeconds
+	// EpochMillis since January 1, 1970 UTC
+	EpochMillis
+	// EpochMicros since January 1, 1970 UTC
+	EpochMicros
+	// EpochNanos since January 1, 1970 UTC
+	EpochNanos
+)
+
+var epochUnitNames = [...]string{
+	EpochAuto:    "auto",
+	EpochSeconds: "s",
+	EpochMillis:  "ms",
+	EpochMicros:  "us",
+	EpochNanos:   "ns",
+}
+
+// String method for EpochUnit
+func (u EpochUnit) String() string {
+	if u < 0 || int(u) >= len(epochUnitNames) {
+		return fmt.Sprintf("EpochUnit(%d)", int(u))
+	}
+	return epochUnitNames[u]
+}
+
+// parseEpochUnit is the inverse of EpochUnit.String
+func parseEpochUnit(s string) (EpochUnit, error) {
+	for u, name := range epochUnitNames {
+		if s == name {
+			return EpochUnit(u), nil
+		}
+	}
+	return EpochAuto, errors.Errorf("invalid epoch unit %q", s)
+}
+
+// perSecond is the number of units in a second
+func (u EpochUnit) perSecond() float64 {
+	switch u {
+	case EpochMillis:
+		return 1e3
+	case EpochMicros:
+		return 1e6
+	case EpochNanos:
+		return 1e9
+	}
+	return 1
+}
+
+// epochUnit detects the unit of epoch n by its magnitude
+func epochUnit(n float64) EpochUnit {
+	n = math.Abs(n)
+	switch {
+	case n < 1e11:
+		return EpochSeconds
+	case n < 1e14:
+		return EpochMillis
+	case n < 1e17:
+		return EpochMicros
+	}
+	return EpochNanos
+}
+
+// Time can be used to decode any JSON value to time.Time.
+// Strings are parsed with the layouts of Options.TimeLayouts,
+// and numbers, or strings that are valid numbers, are Unix epochs
+// with the unit of Options.EpochUnit.
+// Epochs are UTC. Boolean values will error
+type Time = Value[time.Time]
+
+// NullTime can be used to decode any JSON value to time.Time,
+// with the same rules as Time
+type NullTime = Null[time.Time]
+
+// nullTime decodes guregu/null.Time with the same rules as NullTime,
+// see nullTypes
+type nullTime null.Time
+
+func (ft *nullTime) unmarshalJSON(bArr []byte, opts *Options) (err error) {
+	v, valid, err := decode[time.Time](bArr, ft, opts)
+	if err != nil {
+		return err
+	}
+	*ft = nullTime(null.NewTime(v, valid))
+	return
+}
+
+func (ft *nullTime) unmarshalText(k Kind, text []byte, opts *Options) error {
+	v, valid, err := decodeText[time.Time](k, text, ft, opts)
+	if err != nil {
+		return err
+	}
+	*ft = nullTime(null.NewTime(v, valid))
+	return nil
+}
+
+func (ft *nullTime) scan(src any, opts *Options) error {
+	v, valid, err := scan[time.Time](src, ft, opts)
+	if err != nil {
+		return err
+	}
+	*ft = nullTime(null.NewTime(v, valid))
+	return nil
+}
+
+// decodeTime from any JSON value.
+// Strings that are valid numbers are epochs, like numbers
+func decodeTime(k Kind, bArr []byte, opts *Options) (v time.Time, err *CoercionError) {
+	// Value is a...
+	switch k {
+	case KindString:
+		if numK, number := textKind(bArr); numK == KindNumber {
+			return decodeEpoch(k, number, opts)
+		}
+		var parseErr error
+		for _, layout := range opts.timeLayouts() {
+			if v, parseErr = time.Parse(layout, string(bArr)); parseErr == nil {
+				return v, nil
+			}
+		}
+		return v, coercionError(k, ErrInvalidTimeString, parseErr)
+
+	case KindNumber:
+		return decodeEpoch(k, bArr, opts)
+	}
+	return v, kindError(k)
+}
+
+// decodeEpoch decodes the number in bArr to a UTC time
+func decodeEpoch(k Kind, bArr []byte, opts *Options) (v time.Time, err *CoercionError) {
+	s := string(bArr)
+	unit := opts.EpochUnit
+
+	// Integers are exact
+	if !strings.ContainsAny(s, ".eE") {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return v, numberError(k, err)
+		}
+		if unit == EpochAuto {
+			unit = epochUnit(float64(i))
+		}
+		switch unit {
+		case EpochMillis:
+			return time.UnixMilli(i).UTC(), nil
+		case EpochMicros:
+			return time.UnixMicro(i).UTC(), nil
+		case EpochNanos:
+			return time.Unix(0, i).UTC(), nil
+		}
+		return time.Unix(i, 0).UTC(), nil
+	}
+
+	f, parseErr := strconv.ParseFloat(s, 64)
+	if parseErr != nil {
+		return v, numberError(k, parseErr)
+	}
+	if unit == EpochAuto {
+		unit = epochUnit(f)
+	}
+	sec := math.Floor(f / unit.perSecond())
+	i, ok := floatToInt64(sec)
+	if !ok {
+		return v, coercionError(k, ErrOutOfRange, nil)
+	}
+	nsec := math.Round((f/unit.perSecond() - sec) * 1e9)
+	return time.Unix(i, int64(nsec)).UTC(), nil
+}
+
+// formatTime formats t with Options.TimeFormat,
+// number is true for the Unix formats
+func formatTime(t time.Time, opts *Options) (text []byte, number bool) {
+	format := opts.timeFormat()
+	switch format {
+	case TimeFormatUnix:
+		return strconv.AppendInt(nil, t.Unix(), 10), true
+	case TimeFormatUnixMilli:
+		return strconv.AppendInt(nil, t.UnixMilli(), 10), true
+	case TimeFormatUnixMicro:
+		return strconv.AppendInt(nil, t.UnixMicro(), 10), true
+	case TimeFormatUnixNano:
+		return strconv.AppendInt(nil, t.UnixNano(), 10), true
+	}
+	return t.AppendFormat(nil, format), false
+}
+
+// encodeTime as a JSON value, see Options.TimeFormat
+func encodeTime(t time.Time, opts *Options) ([]byte, error) {
+	text, number := formatTime(t, opts)
+	if number {
+		return text, nil
+	}
+	return json.Marshal(string(text))
+}
+
+// timeValue returns src as T if src is a time.Time,
+// and T is time.Time or Date. Times are not formatted and parsed,
+// e.g. database/sql driver values
+func timeValue[T Scalar](src any) (v T, ok bool) {
+	t, ok := src.(time.Time)
+	if !ok {
+		return v, false
+	}
+	switch p := any(&v).(type) {
+	case *time.Time:
+		*p = t
+		return v, true
+	case *Date:
+		*p = DateOf(t)
+		return v, true
+	}
+	return v, false
+}
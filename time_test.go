@@ -0,0 +1,217 @@
+package fuzzy_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTime(t *testing.T) {
+	type Data struct {
+		Time fuzzy.Time `json:"time"`
+	}
+	ts := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		in  string
+		out time.Time
+	}{
+		{`"2024-05-01T10:00:00Z"`, ts},
+		{`"2024-05-01T12:00:00+02:00"`, ts},
+		{`"2024-05-01T10:00:00.5Z"`, ts.Add(500 * time.Millisecond)},
+		{`"2024-05-01 10:00:00"`, ts},
+		{`"2024-05-01T10:00:00"`, ts},
+		{`"2024-05-01 10:00"`, ts},
+		{`"2024-05-01"`, ts.Truncate(24 * time.Hour)},
+		{`"Wed, 01 May 2024 10:00:00 +0000"`, ts},
+		// Epochs by magnitude
+		{`1714557600`, ts},
+		{`1714557600.25`, ts.Add(250 * time.Millisecond)},
+		{`"1714557600000"`, ts},
+		{`1714557600000`, ts},
+		{`1714557600000000`, ts},
+		{`1714557600000000000`, ts},
+		{`1.7145576e9`, ts},
+		{`0`, time.Unix(0, 0).UTC()},
+		{`null`, time.Time{}},
+	} {
+		d := Data{}
+		err := json.Unmarshal([]byte(`{"time": `+tc.in+`}`), &d)
+		require.NoError(t, err, tc.in)
+		require.True(t, tc.out.Equal(d.Time.V),
+			"value must match for %s: %v", tc.in, d.Time.V)
+	}
+
+	d := Data{}
+	err := json.Unmarshal([]byte(`{"time": "yesterday"}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidTimeString)
+	err = json.Unmarshal([]byte(`{"time": true}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrUnsupportedKind)
+	err = json.Unmarshal([]byte(`{"time": 1e300}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrOutOfRange)
+	err = json.Unmarshal([]byte(`{"time": ""}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidTimeString)
+
+	b, err := json.Marshal(Data{Time: fuzzy.Time{V: ts}})
+	require.NoError(t, err)
+	require.Equal(t, `{"time":"2024-05-01T10:00:00Z"}`, string(b), "json must match")
+}
+
+func TestNullTime(t *testing.T) {
+	type Data struct {
+		Time fuzzy.NullTime `json:"time"`
+	}
+	ts := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	d := Data{}
+	err := json.Unmarshal([]byte(`{"time": null}`), &d)
+	require.NoError(t, err)
+	require.False(t, d.Time.Valid, "time must not be valid")
+
+	err = json.Unmarshal([]byte(`{"time": "1714557600"}`), &d)
+	require.NoError(t, err)
+	require.True(t, d.Time.Valid, "time must be valid")
+	require.Equal(t, ts, d.Time.V, "value must match")
+
+	b, err := json.Marshal(d)
+	require.NoError(t, err)
+	require.Equal(t, `{"time":"2024-05-01T10:00:00Z"}`, string(b), "json must match")
+
+	b, err = json.Marshal(Data{})
+	require.NoError(t, err)
+	require.Equal(t, `{"time":null}`, string(b), "json must match")
+
+	// Empty strings are null with the option
+	opts := fuzzy.Options{EmptyString: fuzzy.EmptyNull}
+	err = fuzzy.UnmarshalWithOptions([]byte(`{"time": ""}`), &d, opts)
+	require.NoError(t, err)
+	require.False(t, d.Time.Valid, "time must not be valid")
+
+	// guregu/null.Time is decoded with the same rules
+	type Plain struct {
+		Time null.Time `json:"time"`
+	}
+	p := Plain{}
+	err = fuzzy.Unmarshal([]byte(`{"time": "1714557600"}`), &p)
+	require.NoError(t, err)
+	require.Equal(t, null.TimeFrom(ts), p.Time, "value must match")
+	err = fuzzy.Unmarshal([]byte(`{"time": "yesterday"}`), &p)
+	var cErr *fuzzy.CoercionError
+	require.ErrorAs(t, err, &cErr)
+	require.Equal(t, "null.Time", cErr.Type)
+}
+
+func TestTimeOptions(t *testing.T) {
+	type Data struct {
+		Time fuzzy.Time `json:"time"`
+	}
+	d := Data{}
+	opts := fuzzy.Options{TimeLayouts: []string{"02/01/2006"}}
+	err := fuzzy.UnmarshalWithOptions([]byte(`{"time": "01/05/2024"}`), &d, opts)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), d.Time.V,
+		"value must match")
+	err = fuzzy.UnmarshalWithOptions([]byte(`{"time": "2024-05-01"}`), &d, opts)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidTimeString)
+
+	// The default layouts can be extended with Options.TimeLayouts only
+	layouts := fuzzy.DefaultTimeLayouts()
+	require.Equal(t, time.RFC3339, layouts[0], "layout must match")
+	layouts[0] = "02/01/2006"
+	require.Equal(t, time.RFC3339, fuzzy.DefaultTimeLayouts()[0], "layout must match")
+	err = fuzzy.Unmarshal([]byte(`{"time": "01/05/2024"}`), &d)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidTimeString)
+	opts.TimeLayouts = append(fuzzy.DefaultTimeLayouts(), "02/01/2006")
+	err = fuzzy.UnmarshalWithOptions([]byte(`{"time": "01/05/2024"}`), &d, opts)
+	require.NoError(t, err)
+
+	// Small millisecond epochs are seconds unless the unit is set
+	opts = fuzzy.Options{EpochUnit: fuzzy.EpochMillis}
+	err = fuzzy.UnmarshalWithOptions([]byte(`{"time": 1500}`), &d, opts)
+	require.NoError(t, err)
+	require.Equal(t, time.UnixMilli(1500).UTC(), d.Time.V, "value must match")
+
+	type Tagged struct {
+		Millis fuzzy.NullTime `json:"millis" fuzzy:"epoch=ms"`
+		Day    fuzzy.Time     `json:"day" fuzzy:"layout=02.01.2006|2006-01-02"`
+	}
+	tagged := Tagged{}
+	err = fuzzy.Unmarshal([]byte(`{"millis": "1500", "day": "01.05.2024"}`), &tagged)
+	require.NoError(t, err)
+	require.Equal(t, time.UnixMilli(1500).UTC(), tagged.Millis.V, "value must match")
+	require.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), tagged.Day.V,
+		"value must match")
+
+	type Invalid struct {
+		Time fuzzy.Time `json:"time" fuzzy:"epoch=days"`
+	}
+	err = fuzzy.Unmarshal([]byte(`{"time": 1}`), &Invalid{})
+	require.ErrorContains(t, err, `invalid epoch unit "days"`)
+
+	require.Equal(t, "ms", fuzzy.EpochMillis.String())
+	require.Equal(t, "EpochUnit(99)", fuzzy.EpochUnit(99).String())
+}
+
+func TestTimeFormat(t *testing.T) {
+	ts := fuzzy.Time{V: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+
+	opts := fuzzy.Options{TimeFormat: fuzzy.TimeFormatUnixMilli}
+	b, err := fuzzy.MarshalWithOptions(ts, opts)
+	require.NoError(t, err)
+	require.Equal(t, `1714557600000`, string(b), "json must match")
+
+	opts.TimeFormat = time.DateOnly
+	b, err = fuzzy.MarshalWithOptions(fuzzy.NullTime{V: ts.V, Valid: true}, opts)
+	require.NoError(t, err)
+	require.Equal(t, `"2024-05-01"`, string(b), "json must match")
+
+	// The default format, text is not affected by the options
+	b, err = json.Marshal(ts)
+	require.NoError(t, err)
+	require.Equal(t, `"2024-05-01T10:00:00Z"`, string(b), "json must match")
+	text, err := ts.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, `2024-05-01T10:00:00Z`, string(text), "text must match")
+}
+
+func TestTimeInterfaces(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("SAST", 2*60*60))
+
+	// Driver values are not formatted and parsed
+	var ft fuzzy.Time
+	require.NoError(t, ft.Scan(ts))
+	require.Equal(t, ts, ft.V, "value must match")
+	require.NoError(t, ft.Scan([]byte("2024-05-01 08:00:00")))
+	require.True(t, ts.Equal(ft.V), "value must match")
+	v, err := ft.Value()
+	require.NoError(t, err)
+	require.Equal(t, ft.V, v, "value must match")
+
+	var nt fuzzy.NullTime
+	require.NoError(t, nt.Scan(nil))
+	require.False(t, nt.Valid, "time must not be valid")
+	v, err = nt.Value()
+	require.NoError(t, err)
+	require.Nil(t, v, "value must be nil")
+
+	require.NoError(t, nt.UnmarshalText([]byte("1714550400")))
+	require.True(t, ts.Equal(nt.V), "value must match")
+
+	type Element struct {
+		At    fuzzy.NullTime `xml:"at,attr"`
+		Since fuzzy.Time     `xml:"since"`
+	}
+	e := Element{}
+	err = xml.Unmarshal(
+		[]byte(`<e at="1714550400"><since>2024-05-01T08:00:00Z</since></e>`), &e)
+	require.NoError(t, err)
+	require.True(t, ts.Equal(e.At.V), "value must match")
+	require.True(t, ts.Equal(e.Since.V), "value must match")
+
+	require.NoError(t, ft.Set("2024-05-01"))
+	require.Equal(t, "2024-05-01T00:00:00Z", ft.String(), "flag must match")
+}
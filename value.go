@@ -0,0 +1,324 @@
+package fuzzy
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Scalar is the set of Go types any JSON value can be decoded to.
+// The coercion rules for each type are written once,
+// and shared by Value, Null and the named types, e.g. Int and NullInt
+type Scalar interface {
+	string | int64 | float64 | bool | time.Time | Date | time.Duration |
+		Decimal | BigInt
+}
+
+// Value can be used to decode any JSON value to T.
+// JSON null decodes to the zero value of T
+type Value[T Scalar] struct {
+	V T
+}
+
+// MarshalJSON method for Value
+func (fv Value[T]) MarshalJSON() ([]byte, error) {
+	return fv.marshalJSON(&defaultOptions)
+}
+
+func (fv Value[T]) marshalJSON(opts *Options) ([]byte, error) {
+	return encode(fv.V, opts)
+}
+
+// UnmarshalJSON method for Value
+func (fv *Value[T]) UnmarshalJSON(bArr []byte) (err error) {
+	return fv.unmarshalJSON(bArr, &defaultOptions)
+}
+
+func (fv *Value[T]) unmarshalJSON(bArr []byte, opts *Options) (err error) {
+	v, _, err := decode[T](bArr, fv, opts)
+	if err != nil {
+		return err
+	}
+	fv.V = v
+	return
+}
+
+// Null can be used to decode any JSON value to T.
+// JSON null decodes to an invalid value
+type Null[T Scalar] sql.Null[T]
+
+// MarshalJSON method for Null
+func (fn Null[T]) MarshalJSON() ([]byte, error) {
+	return fn.marshalJSON(&defaultOptions)
+}
+
+func (fn Null[T]) marshalJSON(opts *Options) ([]byte, error) {
+	if !fn.Valid {
+		return []byte(`null`), nil
+	}
+	return encode(fn.V, opts)
+}
+
+// UnmarshalJSON method for Null
+func (fn *Null[T]) UnmarshalJSON(bArr []byte) (err error) {
+	return fn.unmarshalJSON(bArr, &defaultOptions)
+}
+
+func (fn *Null[T]) unmarshalJSON(bArr []byte, opts *Options) (err error) {
+	v, valid, err := decode[T](bArr, fn, opts)
+	if err != nil {
+		return err
+	}
+	*fn = Null[T]{V: v, Valid: valid}
+	return
+}
+
+// unmarshaler is implemented by fuzzy types to decode with options,
+// UnmarshalJSON uses the default options
+type unmarshaler interface {
+	unmarshalJSON(bArr []byte, opts *Options) error
+}
+
+// marshaler is implemented by fuzzy types to encode with options,
+// MarshalJSON uses the default options
+type marshaler interface {
+	marshalJSON(opts *Options) ([]byte, error)
+}
+
+// decode any JSON value to T.
+// Valid is false if the value is null, v is then the zero value of T.
+// The kind of value is detected from the first byte,
+// and the value is parsed once.
+// Errors are of type *CoercionError, dst is used for the type name
+func decode[T Scalar](bArr []byte, dst any, opts *Options) (v T, valid bool, err error) {
+	bArr = trim(bArr)
+	k, content, err := jsonValue(bArr, dst)
+	if err != nil {
+		return v, false, err
+	}
+	return coerce[T](k, content, bArr, dst, opts)
+}
+
+// jsonValue returns the kind and content of the trimmed JSON value in bArr,
+// strings are unquoted once, and passed on as content
+func jsonValue(bArr []byte, dst any) (k Kind, content []byte, err error) {
+	k = kindOf(bArr)
+	if k != KindString {
+		return k, bArr, nil
+	}
+	content, err = unquoteBytes(bArr)
+	if err != nil {
+		return k, nil, withValue(
+			coercionError(k, ErrInvalidJSON, err), dst, bArr)
+	}
+	return k, content, nil
+}
+
+// coerce a value of kind k to T.
+// For strings content is the unquoted string,
+// for other kinds it's the literal JSON text.
+// The raw value is used for errors
+func coerce[T Scalar](
+	k Kind, content []byte, raw []byte, dst any, opts *Options) (
+	v T, valid bool, err error) {
+
+	// Value is null
+	if k == KindNull {
+		return v, false, nil
+	}
+
+	if k == KindString {
+		if opts.TrimSpace {
+			content = bytes.TrimSpace(content)
+		}
+		if len(content) == 0 {
+			switch opts.EmptyString {
+			case EmptyZero:
+				return v, true, nil
+			case EmptyNull:
+				return v, false, nil
+			}
+		}
+	}
+
+	var cErr *CoercionError
+	switch p := any(&v).(type) {
+	case *string:
+		*p, cErr = decodeString(k, content)
+	case *int64:
+		*p, cErr = decodeInt(k, content, opts)
+	case *float64:
+		*p, cErr = decodeFloat(k, content, opts)
+	case *bool:
+		*p, cErr = decodeBool(k, content, opts)
+	case *time.Time:
+		*p, cErr = decodeTime(k, content, opts)
+	case *Date:
+		*p, cErr = decodeDate(k, content, opts)
+	case *time.Duration:
+		*p, cErr = decodeDuration(k, content, opts)
+	case *Decimal:
+		*p, cErr = decodeDecimal(k, content, opts)
+	case *BigInt:
+		*p, cErr = decodeBigInt(k, content, opts)
+	}
+	if cErr != nil {
+		var zero T
+		return zero, false, withValue(cErr, dst, raw)
+	}
+	return v, true, nil
+}
+
+// encode T as a JSON value, formatted by opts
+func encode[T Scalar](v T, opts *Options) ([]byte, error) {
+	switch v := any(v).(type) {
+	case string:
+		return json.Marshal(v)
+	case int64:
+		return []byte(strconv.FormatInt(v, 10)), nil
+	case float64:
+		return []byte(strconv.FormatFloat(v, 'f', -1, 64)), nil
+	case bool:
+		return []byte(strconv.FormatBool(v)), nil
+	case time.Time:
+		return encodeTime(v, opts)
+	case Date:
+		return json.Marshal(v.String())
+	case time.Duration:
+		return encodeDuration(v, opts)
+	case Decimal:
+		return []byte(v.String()), nil
+	case BigInt:
+		return encodeBigInt(v, opts)
+	}
+	return nil, errors.WithStack(fmt.Errorf("unsupported type %T", v))
+}
+
+// The decode functions below are called by coerce for each kind of value,
+// except null. For strings bArr is the unquoted content
+
+// decodeString from any JSON value.
+// Numbers and bools are decoded to their literal JSON text
+func decodeString(k Kind, bArr []byte) (v string, err *CoercionError) {
+	// Value is a...
+	switch k {
+	case KindString, KindNumber, KindBool:
+		return string(bArr), nil
+	}
+	return v, kindError(k)
+}
+
+// decodeInt from any JSON value.
+// Strings that are not valid representation of a number will error,
+// floats are rounded, and bools will error
+func decodeInt(k Kind, bArr []byte, opts *Options) (v int64, err *CoercionError) {
+	// Value is a...
+	switch k {
+	case KindString:
+		v, err := strconv.ParseInt(string(bArr), 10, 64)
+		if err != nil {
+			return v, numberError(k, err)
+		}
+		return v, nil
+
+	case KindNumber:
+		// int
+		if bytes.IndexAny(bArr, ".eE") == -1 {
+			i, err := strconv.ParseInt(string(bArr), 10, 64)
+			if err == nil {
+				return i, nil
+			}
+		}
+		// float, or int out of range
+		f, err := strconv.ParseFloat(string(bArr), 64)
+		if err != nil {
+			return v, numberError(k, err)
+		}
+		v, err = opts.round(f)
+		if err != nil {
+			return v, coercionError(k, err, nil)
+		}
+		return v, nil
+
+	case KindBool:
+		if opts.BoolToNumber {
+			return boolToNumber[int64](bArr), nil
+		}
+		return v, coercionError(k, ErrBoolToNumber, nil)
+	}
+	return v, kindError(k)
+}
+
+// decodeFloat from any JSON value.
+// Strings that are not valid representation of a number will error,
+// and bools will error
+func decodeFloat(k Kind, bArr []byte, opts *Options) (v float64, err *CoercionError) {
+	// Value is a...
+	switch k {
+	case KindString, KindNumber:
+		v, err := strconv.ParseFloat(string(bArr), 64)
+		if err != nil {
+			return v, numberError(k, err)
+		}
+		return v, nil
+
+	case KindBool:
+		if opts.BoolToNumber {
+			return boolToNumber[float64](bArr), nil
+		}
+		return v, coercionError(k, ErrBoolToNumber, nil)
+	}
+	return v, kindError(k)
+}
+
+// boolToNumber returns 1 for the JSON literal true, and 0 for false
+func boolToNumber[T int64 | float64](bArr []byte) T {
+	if bArr[0] == 't' {
+		return 1
+	}
+	return 0
+}
+
+// floatToInt64 converts the integral float f to an int64,
+// ok is false if f is out of range or NaN
+func floatToInt64(f float64) (i int64, ok bool) {
+	// Float64 can represent -2^63 exactly, but not 2^63-1
+	if f < math.MinInt64 || f >= math.MaxInt64 || math.IsNaN(f) {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// decodeBool from any JSON value.
+// Empty strings as well as "false" and "0" evaluate to false,
+// all other strings are true, unless the vocabulary is set in opts.
+// Numbers equal to 0 will evaluate to false,
+// all other numbers are true
+func decodeBool(k Kind, bArr []byte, opts *Options) (v bool, err *CoercionError) {
+	// Value is a...
+	switch k {
+	case KindString:
+		v, ok := opts.parseBool(string(bArr))
+		if !ok {
+			return v, coercionError(k, ErrInvalidBoolString, nil)
+		}
+		return v, nil
+
+	case KindNumber:
+		f, err := strconv.ParseFloat(string(bArr), 64)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return v, numberError(k, err)
+		}
+		return f != 0, nil
+
+	case KindBool:
+		return bArr[0] == 't', nil
+	}
+	return v, kindError(k)
+}
@@ -0,0 +1,117 @@
+package fuzzy_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValue(t *testing.T) {
+	type Data struct {
+		String fuzzy.Value[string]  `json:"string"`
+		Int    fuzzy.Value[int64]   `json:"int"`
+		Float  fuzzy.Value[float64] `json:"float"`
+		Bool   fuzzy.Value[bool]    `json:"bool"`
+	}
+	d := Data{}
+
+	// null
+	b := []byte(`{"string": null, "int": null, "float": null, "bool": null}`)
+	err := json.Unmarshal(b, &d)
+	require.NoError(t, err)
+	require.Equal(t, Data{}, d, "value must match")
+
+	// string
+	b = []byte(`{"string": "123", "int": "-123", "float": "1.618", "bool": "0"}`)
+	err = json.Unmarshal(b, &d)
+	require.NoError(t, err)
+	require.Equal(t, "123", d.String.V, "value must match")
+	require.Equal(t, int64(-123), d.Int.V, "value must match")
+	require.Equal(t, float64(1.618), d.Float.V, "value must match")
+	require.Equal(t, false, d.Bool.V, "value must match")
+
+	// int
+	b = []byte(`{"string": 123, "int": 123, "float": -1, "bool": -1}`)
+	err = json.Unmarshal(b, &d)
+	require.NoError(t, err)
+	require.Equal(t, "123", d.String.V, "value must match")
+	require.Equal(t, int64(123), d.Int.V, "value must match")
+	require.Equal(t, float64(-1), d.Float.V, "value must match")
+	require.Equal(t, true, d.Bool.V, "value must match")
+
+	// float
+	b = []byte(`{"string": -123.456, "int": -123.456, "float": -1.618, "bool": 0.0}`)
+	err = json.Unmarshal(b, &d)
+	require.NoError(t, err)
+	require.Equal(t, "-123.456", d.String.V, "value must match")
+	require.Equal(t, int64(-123), d.Int.V, "value must match")
+	require.Equal(t, float64(-1.618), d.Float.V, "value must match")
+	require.Equal(t, false, d.Bool.V, "value must match")
+
+	// bool
+	b = []byte(`{"string": true, "bool": true}`)
+	err = json.Unmarshal(b, &d)
+	require.NoError(t, err)
+	require.Equal(t, "true", d.String.V, "value must match")
+	require.Equal(t, true, d.Bool.V, "value must match")
+
+	b = []byte(`{"int": true}`)
+	err = json.Unmarshal(b, &d)
+	require.Error(t, err)
+
+	b = []byte(`{"float": false}`)
+	err = json.Unmarshal(b, &d)
+	require.Error(t, err)
+
+	// marshal
+	d = Data{}
+	d.String.V = "foo"
+	d.Int.V = 123
+	d.Float.V = 1.618
+	d.Bool.V = true
+	b, err = json.Marshal(d)
+	require.NoError(t, err)
+	require.Equal(t, `{"string":"foo","int":123,"float":1.618,"bool":true}`, string(b))
+}
+
+func TestNull(t *testing.T) {
+	type Data struct {
+		String fuzzy.Null[string]  `json:"string"`
+		Int    fuzzy.Null[int64]   `json:"int"`
+		Float  fuzzy.Null[float64] `json:"float"`
+		Bool   fuzzy.Null[bool]    `json:"bool"`
+	}
+	d := Data{}
+
+	// null
+	b := []byte(`{"string": null, "int": null, "float": null, "bool": null}`)
+	err := json.Unmarshal(b, &d)
+	require.NoError(t, err)
+	require.Equal(t, Data{}, d, "values must not be valid")
+
+	b, err = json.Marshal(d)
+	require.NoError(t, err)
+	require.Equal(t, `{"string":null,"int":null,"float":null,"bool":null}`, string(b))
+
+	// string
+	b = []byte(`{"string": "", "int": "0", "float": "0", "bool": ""}`)
+	err = json.Unmarshal(b, &d)
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.Null[string]{V: "", Valid: true}, d.String)
+	require.Equal(t, fuzzy.Null[int64]{V: 0, Valid: true}, d.Int)
+	require.Equal(t, fuzzy.Null[float64]{V: 0, Valid: true}, d.Float)
+	require.Equal(t, fuzzy.Null[bool]{V: false, Valid: true}, d.Bool)
+
+	b, err = json.Marshal(d)
+	require.NoError(t, err)
+	require.Equal(t, `{"string":"","int":0,"float":0,"bool":false}`, string(b))
+
+	// invalid
+	b = []byte(`{"int": "abc"}`)
+	err = json.Unmarshal(b, &d)
+	require.Error(t, err)
+	require.Equal(t, fuzzy.Null[int64]{V: 0, Valid: true}, d.Int,
+		"value must not change on error")
+}
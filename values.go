@@ -0,0 +1,392 @@
+package fuzzy
+
+import (
+	"encoding"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DecodeValues decodes url.Values, e.g. a parsed query string or form post,
+// to the struct pointed to by v.
+// Keys are matched to fields by the form tag, or the json tag,
+// so the same struct can be decoded from JSON and forms.
+// Values are decoded with the same rules as JSON strings, and
+//   - the last value of a repeated key is used for non-slice fields
+//   - repeated keys, or keys like tags[], are decoded to slices
+//   - bracketed keys like items[0][qty] are decoded to nested
+//     structs, slices and maps
+//   - bool fields that are missing are set to false,
+//     like unchecked checkboxes, and "on" is true
+//
+// Errors are located by the JSON Pointer of the value, e.g. /items/0/qty
+func DecodeValues(values url.Values, v any) error {
+	return DecodeValuesWithOptions(values, v, Options{})
+}
+
+// DecodeValuesWithOptions is like DecodeValues,
+// opts apply to all values decoded to v
+func DecodeValuesWithOptions(values url.Values, v any, opts Options) error {
+	return decodeValues(values, v, opts, true)
+}
+
+// decodeValues to v, checkbox sets missing bool fields to false
+func decodeValues(values url.Values, v any, opts Options, checkbox bool) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.Errorf("fuzzy: DecodeValues needs a non-nil pointer, not %T", v)
+	}
+	d := decodeState{opts: opts, checkbox: checkbox}
+	if err := d.form(parseValues(values), rv); err != nil {
+		return err
+	}
+	if len(d.errs) > 0 {
+		return d.errs
+	}
+	return nil
+}
+
+// formNode is a key of url.Values parsed into a tree,
+// e.g. items[0][qty] is the node qty of node 0 of node items
+type formNode struct {
+	values   []string
+	children map[string]*formNode
+	// keys of the children in order
+	keys []string
+}
+
+// child returns the child node for key, adding it if it's missing
+func (n *formNode) child(key string) *formNode {
+	c, ok := n.children[key]
+	if !ok {
+		if n.children == nil {
+			n.children = map[string]*formNode{}
+		}
+		c = &formNode{}
+		n.children[key] = c
+		n.keys = append(n.keys, key)
+	}
+	return c
+}
+
+// parseValues into a tree of nodes, keys are visited in sorted order
+func parseValues(values url.Values) *formNode {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	root := &formNode{}
+	for _, key := range keys {
+		n := root
+		for _, token := range splitKey(key) {
+			n = n.child(token)
+		}
+		n.values = append(n.values, values[key]...)
+	}
+	return root
+}
+
+// splitKey splits a bracketed key like items[0][qty] into its tokens.
+// Trailing empty brackets are dropped, i.e. tags[] is the same as tags.
+// Keys that are not well-formed are a single token
+func splitKey(key string) []string {
+	name, rest, ok := strings.Cut(key, "[")
+	if !ok || name == "" {
+		return []string{key}
+	}
+	tokens := []string{name}
+	rest = "[" + rest
+	for rest != "" {
+		if rest[0] != '[' {
+			return []string{key}
+		}
+		token, next, ok := strings.Cut(rest[1:], "]")
+		if !ok {
+			return []string{key}
+		}
+		tokens = append(tokens, token)
+		rest = next
+	}
+	for len(tokens) > 1 && tokens[len(tokens)-1] == "" {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+// form decodes node n to v
+func (d *decodeState) form(n *formNode, v reflect.Value) error {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if isText(v) {
+		return d.formText(n, v)
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return d.formStruct(n, v)
+	case reflect.Map:
+		return d.formMap(n, v)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if s, ok := last(n); ok {
+				v.SetBytes([]byte(s))
+			}
+			return nil
+		}
+		return d.formSlice(n, v)
+	case reflect.Array:
+		return d.formSlice(n, v)
+	case reflect.Interface:
+		if s, ok := last(n); ok && v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(s))
+		}
+		return nil
+	}
+	return errors.Errorf("fuzzy: unsupported type %v at %s", v.Type(), d.pointer())
+}
+
+// isText returns true if v is decoded from a single value
+func isText(v reflect.Value) bool {
+	if _, ok := v.Addr().Interface().(textUnmarshaler); ok {
+		return true
+	}
+	if _, ok := fuzzyNull[textUnmarshaler](v); ok {
+		return true
+	}
+	return v.Addr().Type().Implements(textUnmarshalerType) ||
+		isSQLNull(v.Type()) || isScalar(v)
+}
+
+// last value of node n, the last of repeated keys wins
+func last(n *formNode) (s string, ok bool) {
+	if len(n.values) == 0 {
+		return "", false
+	}
+	return n.values[len(n.values)-1], true
+}
+
+// formText decodes the last value of node n to v, like a JSON string
+func (d *decodeState) formText(n *formNode, v reflect.Value) error {
+	s, ok := last(n)
+	if !ok {
+		// Only bracketed keys, e.g. qty[a] for a number
+		err := withValue(coercionError(KindObject, ErrUnsupportedKind, nil),
+			v.Addr().Interface(), nil)
+		return d.recoverError(d.locateKey(err), v)
+	}
+	k, text := KindString, []byte(s)
+	if d.numbers {
+		k, text = textKind(text)
+	}
+
+	var err error
+	if u, ok := v.Addr().Interface().(textUnmarshaler); ok {
+		err = u.unmarshalText(k, text, &d.opts)
+	} else if u, ok := fuzzyNull[textUnmarshaler](v); ok {
+		err = typed(u.unmarshalText(k, text, &d.opts), v.Type())
+	} else if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		err = errors.WithStack(u.UnmarshalText([]byte(s)))
+	} else if isSQLNull(v.Type()) {
+		var valid bool
+		valid, err = scalar(k, text, text, v.Field(0), &d.opts)
+		if err == nil {
+			v.Field(1).SetBool(valid)
+		}
+		err = typed(err, v.Type())
+	} else {
+		_, err = scalar(k, text, text, v, &d.opts)
+	}
+	return d.recoverError(d.locateKey(err), v)
+}
+
+// formStruct decodes the children of node n to the fields of struct v
+func (d *decodeState) formStruct(n *formNode, v reflect.Value) error {
+	fields := cachedFields(v.Type(), "form,json")
+	for _, key := range n.keys {
+		d.path = append(d.path, key)
+		err := d.formField(n.children[key], v, fields, key)
+		d.path = d.path[:len(d.path)-1]
+		if err != nil {
+			return err
+		}
+	}
+
+	if !d.checkbox {
+		return nil
+	}
+
+	// Missing bools are unchecked checkboxes
+	for _, f := range fields {
+		if f.typ.Kind() != reflect.Bool || lookupKey(n, f.name) {
+			continue
+		}
+		if fv := fieldByIndex(v, f.index); fv.IsValid() {
+			fv.SetBool(false)
+		}
+	}
+	return nil
+}
+
+// lookupKey returns true if node n has a child matching the field name,
+// like lookupField the match is case-insensitive
+func lookupKey(n *formNode, name string) bool {
+	for _, key := range n.keys {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// formField decodes node n to the struct field named key
+func (d *decodeState) formField(
+	n *formNode, v reflect.Value, fields []field, key string) error {
+
+	f := lookupField(fields, key)
+	if f == nil {
+		if d.opts.DisallowUnknownFields {
+			return errors.Errorf("fuzzy: unknown key %q", key)
+		}
+		return nil
+	}
+	fv, err := f.value(v)
+	if err != nil {
+		return err
+	}
+	restore, err := d.fieldOptions(f)
+	if err != nil {
+		return err
+	}
+	defer restore()
+	return d.form(n, fv)
+}
+
+// formMap decodes the children of node n to map v
+func (d *decodeState) formMap(n *formNode, v reflect.Value) error {
+	t := v.Type()
+	if !validMapKey(t.Key()) {
+		return errors.Errorf("fuzzy: unsupported type %v at %s", t, d.pointer())
+	}
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(t))
+	}
+	for _, key := range n.keys {
+		d.path = append(d.path, key)
+		err := d.formMapIndex(n.children[key], v, key)
+		d.path = d.path[:len(d.path)-1]
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formMapIndex decodes node n to a new map element with key
+func (d *decodeState) formMapIndex(n *formNode, v reflect.Value, key string) error {
+	t := v.Type()
+	kv, err := mapKey(t.Key(), key)
+	if err != nil {
+		return d.locateKey(err)
+	}
+	ev := reflect.New(t.Elem()).Elem()
+	if err = d.form(n, ev); err != nil {
+		return err
+	}
+	v.SetMapIndex(kv, ev)
+	return nil
+}
+
+// formSlice decodes node n to slice or array v.
+// Repeated keys are elements in order,
+// and bracketed keys like items[0] are elements by index,
+// indexes are limited by Options.MaxSliceLen
+func (d *decodeState) formSlice(n *formNode, v reflect.Value) error {
+	elem := reflect.New(v.Type().Elem()).Elem()
+	for elem.Kind() == reflect.Pointer {
+		elem = reflect.New(elem.Type().Elem()).Elem()
+	}
+	values := []string(nil)
+	if isText(elem) {
+		values = n.values
+	}
+
+	// Length is the max of repeated keys and indexes
+	length := len(values)
+	indexes := make([]int, len(n.keys))
+	for i, key := range n.keys {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 {
+			return errors.Errorf("fuzzy: invalid index %q at %s", key, d.pointer())
+		}
+		if v.Kind() == reflect.Slice && index >= d.opts.maxSliceLen() {
+			return errors.Errorf("fuzzy: index %d exceeds max slice length %d at %s",
+				index, d.opts.maxSliceLen(), d.pointer())
+		}
+		indexes[i] = index
+		length = max(length, index+1)
+	}
+	if v.Kind() == reflect.Array {
+		if length > v.Len() {
+			return errors.Errorf("fuzzy: index %d out of range for %v at %s",
+				length-1, v.Type(), d.pointer())
+		}
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).SetZero()
+		}
+	} else {
+		v.Set(reflect.MakeSlice(v.Type(), length, length))
+	}
+
+	for i, s := range values {
+		d.path = append(d.path, strconv.Itoa(i))
+		err := d.form(&formNode{values: []string{s}}, v.Index(i))
+		d.path = d.path[:len(d.path)-1]
+		if err != nil {
+			return err
+		}
+	}
+	for i, key := range n.keys {
+		d.path = append(d.path, key)
+		err := d.form(n.children[key], v.Index(indexes[i]))
+		d.path = d.path[:len(d.path)-1]
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// locateKey sets the path on errors returned for the current value,
+// the offset is -1 since the input is not JSON
+func (d *decodeState) locateKey(err error) error {
+	if err == nil {
+		return nil
+	}
+	var cErr *CoercionError
+	if errors.As(err, &cErr) && !cErr.located {
+		cErr.Path = d.pointer()
+		cErr.Offset = -1
+		cErr.located = true
+	}
+	return err
+}
+
+// unlocate clears the path of a CoercionError,
+// for values that are not part of a document, e.g. a flag
+func unlocate(err error) error {
+	var cErr *CoercionError
+	if errors.As(err, &cErr) {
+		cErr.Path, cErr.Offset, cErr.located = "", 0, false
+	}
+	return err
+}
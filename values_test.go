@@ -0,0 +1,160 @@
+package fuzzy_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+type Form struct {
+	Name     fuzzy.String       `json:"name"`
+	Qty      fuzzy.Int          `form:"quantity" json:"qty"`
+	Price    fuzzy.NullFloat    `json:"price"`
+	Paid     fuzzy.Bool         `json:"paid"`
+	Gift     bool               `json:"gift"`
+	Express  fuzzy.NullBool     `json:"express"`
+	Tags     []string           `json:"tags"`
+	IDs      []fuzzy.Int        `json:"ids"`
+	Items    []FormItem         `json:"items"`
+	Meta     map[string]int64   `json:"meta"`
+	Note     *fuzzy.String      `json:"note"`
+	Discount null.Float         `json:"discount"`
+	Flags    map[string][]bool  `json:"flags"`
+	Extra    fuzzy.Null[string] `json:"-"`
+}
+
+type FormItem struct {
+	SKU    string    `json:"sku"`
+	Qty    fuzzy.Int `json:"qty" fuzzy:"round=half-up"`
+	Active bool      `json:"active"`
+}
+
+func TestDecodeValues(t *testing.T) {
+	values, err := url.ParseQuery(
+		"name=Alice&quantity=2&price=9.99&paid=on&express=0" +
+			"&tags=a&tags=b&ids[]=1&ids[]=2" +
+			"&items[1][sku]=B&items[1][qty]=3&items[0][sku]=A&items[0][active]=1" +
+			"&meta[x]=1&meta[y]=-2&note=hi&discount=0.5&flags[a][]=1&flags[a][]=0" +
+			"&Extra=ignored&unknown=1")
+	require.NoError(t, err)
+
+	f := Form{Gift: true, Extra: fuzzy.Null[string]{V: "keep", Valid: true}}
+	err = fuzzy.DecodeValues(values, &f)
+	require.NoError(t, err)
+	note := fuzzy.String("hi")
+	require.Equal(t, Form{
+		Name:    "Alice",
+		Qty:     2,
+		Price:   fuzzy.NullFloat(null.FloatFrom(9.99)),
+		Paid:    true,
+		Gift:    false, // Unchecked
+		Express: fuzzy.NullBool(null.BoolFrom(false)),
+		Tags:    []string{"a", "b"},
+		IDs:     []fuzzy.Int{1, 2},
+		Items: []FormItem{
+			{SKU: "A", Active: true},
+			{SKU: "B", Qty: 3},
+		},
+		Meta:     map[string]int64{"x": 1, "y": -2},
+		Note:     &note,
+		Discount: null.FloatFrom(0.5),
+		Flags:    map[string][]bool{"a": {true, false}},
+		Extra:    fuzzy.Null[string]{V: "keep", Valid: true},
+	}, f)
+
+	// The last of repeated keys wins for other fields
+	f = Form{}
+	err = fuzzy.DecodeValues(url.Values{"paid": {"false", "on"}, "quantity": {"1", "2"}}, &f)
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.Bool(true), f.Paid)
+	require.Equal(t, fuzzy.Int(2), f.Qty)
+
+	// Same struct from JSON
+	jf := Form{}
+	err = fuzzy.Unmarshal([]byte(`{"qty": "2", "items": [{"sku": "A", "qty": 1.5}]}`), &jf)
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.Int(2), jf.Qty)
+	require.Equal(t, fuzzy.Int(2), jf.Items[0].Qty)
+}
+
+func TestDecodeValuesErrors(t *testing.T) {
+	for _, tc := range []struct {
+		query  string
+		reason error
+		msg    string
+	}{
+		{"quantity=abc", fuzzy.ErrInvalidNumberString,
+			`fuzzy: cannot decode string abc to fuzzy.Int at /quantity: string is not a valid number: strconv.ParseInt: parsing "abc": invalid syntax`},
+		{"items[2][qty]=x", fuzzy.ErrInvalidNumberString, ""},
+		{"meta[x]=1.5", fuzzy.ErrInvalidNumberString,
+			`fuzzy: cannot decode string 1.5 to int64 at /meta/x: string is not a valid number: strconv.ParseInt: parsing "1.5": invalid syntax`},
+		{"price[a]=1", fuzzy.ErrUnsupportedKind,
+			`fuzzy: cannot decode object to fuzzy.NullFloat at /price: unsupported kind`},
+		{"discount=x", fuzzy.ErrInvalidNumberString, ""},
+	} {
+		values, err := url.ParseQuery(tc.query)
+		require.NoError(t, err)
+		err = fuzzy.DecodeValues(values, &Form{})
+		require.ErrorIs(t, err, tc.reason, tc.query)
+		if tc.msg != "" {
+			require.Equal(t, tc.msg, err.Error())
+		}
+	}
+
+	err := fuzzy.DecodeValues(url.Values{"items[x][qty]": {"1"}}, &Form{})
+	require.EqualError(t, err, `fuzzy: invalid index "x" at /items`)
+
+	// Indexes are limited, the slice is allocated up to the max index
+	err = fuzzy.DecodeValues(url.Values{"items[50000000][qty]": {"1"}}, &Form{})
+	require.EqualError(t, err,
+		`fuzzy: index 50000000 exceeds max slice length 1000 at /items`)
+	err = fuzzy.DecodeValues(url.Values{"items[999][qty]": {"1"}}, &Form{})
+	require.NoError(t, err)
+	err = fuzzy.DecodeValuesWithOptions(url.Values{"items[10][qty]": {"1"}},
+		&Form{}, fuzzy.Options{MaxSliceLen: 10})
+	require.EqualError(t, err,
+		`fuzzy: index 10 exceeds max slice length 10 at /items`)
+
+	// Errors name the type of guregu/null fields
+	err = fuzzy.DecodeValues(url.Values{"discount": {"x"}}, &Form{})
+	var cErr *fuzzy.CoercionError
+	require.ErrorAs(t, err, &cErr)
+	require.Equal(t, "null.Float", cErr.Type)
+	require.Equal(t, "/discount", cErr.Path)
+	require.Equal(t, int64(-1), cErr.Offset)
+
+	// Options
+	f := Form{}
+	err = fuzzy.DecodeValuesWithOptions(url.Values{
+		"quantity": {"x"}, "ids": {"1", "y"}, "paid": {"maybe"},
+	}, &f, fuzzy.Options{CollectErrors: true, BoolTrue: []string{"yes"}})
+	var errs fuzzy.Errors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 3)
+	require.Equal(t, "/ids/1", errs[0].Path)
+	require.Equal(t, "/paid", errs[1].Path)
+	require.Equal(t, "/quantity", errs[2].Path)
+	require.Equal(t, []fuzzy.Int{1, 0}, f.IDs)
+
+	err = fuzzy.DecodeValuesWithOptions(url.Values{"x": {"1"}}, &Form{},
+		fuzzy.Options{DisallowUnknownFields: true})
+	require.EqualError(t, err, `fuzzy: unknown key "x"`)
+
+	err = fuzzy.DecodeValues(url.Values{}, Form{})
+	require.EqualError(t, err,
+		"fuzzy: DecodeValues needs a non-nil pointer, not fuzzy_test.Form")
+}
+
+func TestDecodeValuesJSON(t *testing.T) {
+	// Errors are located by the same path as JSON
+	b := []byte(`{"items": [{}, {}, {"qty": "x"}]}`)
+	jsonErr := fuzzy.Unmarshal(b, &Form{})
+	formErr := fuzzy.DecodeValues(url.Values{"items[2][qty]": {"x"}}, &Form{})
+	var jErr, fErr *fuzzy.CoercionError
+	require.ErrorAs(t, jsonErr, &jErr)
+	require.ErrorAs(t, formErr, &fErr)
+	require.Equal(t, jErr.Path, fErr.Path)
+}
@@ -0,0 +1,400 @@
+package fuzzy
+
+import (
+	"bytes"
+	"encoding/xml"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+)
+
+// xsiNamespace is the namespace of the xsi:nil attribute
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// decodeXML reads the element start and decodes its character data to T.
+// Empty elements and elements with xsi:nil="true" are null
+func decodeXML[T Scalar](d *xml.Decoder, start xml.StartElement, dst any) (
+	v T, valid bool, err error) {
+
+	var s string
+	if err = d.DecodeElement(&s, &start); err != nil {
+		return v, false, errors.WithStack(err)
+	}
+	if isNil(start) {
+		return v, false, nil
+	}
+	return decodeXMLText[T]([]byte(s), dst)
+}
+
+// isNil returns true if the element has the xsi:nil attribute set
+func isNil(start xml.StartElement) bool {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "nil" &&
+			(attr.Name.Space == xsiNamespace || attr.Name.Space == "xsi") {
+			return attr.Value == "true" || attr.Value == "1"
+		}
+	}
+	return false
+}
+
+// decodeXMLText decodes the value of an element or attribute to T.
+// Empty values are null, values that are valid JSON numbers are decoded
+// like JSON numbers, e.g. 12.0 for Int, and other values like JSON strings
+func decodeXMLText[T Scalar](text []byte, dst any) (v T, valid bool, err error) {
+	if len(bytes.TrimSpace(text)) == 0 {
+		return v, false, nil
+	}
+	k, text := textKind(text)
+	return coerce[T](k, text, text, dst, &defaultOptions)
+}
+
+// encodeXML writes the element start with text,
+// invalid values are empty elements with xsi:nil="true"
+func encodeXML(e *xml.Encoder, start xml.StartElement, text []byte, valid bool) error {
+	if !valid {
+		start.Attr = append(start.Attr,
+			xml.Attr{Name: xml.Name{Local: "xmlns:xsi"}, Value: xsiNamespace},
+			xml.Attr{Name: xml.Name{Local: "xsi:nil"}, Value: "true"})
+		text = nil
+	}
+	return e.EncodeElement(string(text), start)
+}
+
+// encodeXMLAttr returns the attribute with text,
+// invalid values are omitted
+func encodeXMLAttr(name xml.Name, text []byte, valid bool) (xml.Attr, error) {
+	if !valid {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: string(text)}, nil
+}
+
+// UnmarshalXML method for String,
+// empty elements and xsi:nil are the zero value
+func (fs *String) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v, _, err := decodeXML[string](d, start, fs)
+	if err != nil {
+		return err
+	}
+	*fs = String(v)
+	return nil
+}
+
+// UnmarshalXMLAttr method for String,
+// empty attributes are the zero value
+func (fs *String) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, _, err := decodeXMLText[string]([]byte(attr.Value), fs)
+	if err != nil {
+		return err
+	}
+	*fs = String(v)
+	return nil
+}
+
+// MarshalXML method for String
+func (fs String) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXML(e, start, encodeText(string(fs)), true)
+}
+
+// MarshalXMLAttr method for String
+func (fs String) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return encodeXMLAttr(name, encodeText(string(fs)), true)
+}
+
+// UnmarshalXML method for Int,
+// empty elements and xsi:nil are the zero value
+func (fi *Int) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v, _, err := decodeXML[int64](d, start, fi)
+	if err != nil {
+		return err
+	}
+	*fi = Int(v)
+	return nil
+}
+
+// UnmarshalXMLAttr method for Int,
+// empty attributes are the zero value
+func (fi *Int) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, _, err := decodeXMLText[int64]([]byte(attr.Value), fi)
+	if err != nil {
+		return err
+	}
+	*fi = Int(v)
+	return nil
+}
+
+// MarshalXML method for Int
+func (fi Int) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXML(e, start, encodeText(int64(fi)), true)
+}
+
+// MarshalXMLAttr method for Int
+func (fi Int) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return encodeXMLAttr(name, encodeText(int64(fi)), true)
+}
+
+// UnmarshalXML method for Float,
+// empty elements and xsi:nil are the zero value
+func (fi *Float) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v, _, err := decodeXML[float64](d, start, fi)
+	if err != nil {
+		return err
+	}
+	*fi = Float(v)
+	return nil
+}
+
+// UnmarshalXMLAttr method for Float,
+// empty attributes are the zero value
+func (fi *Float) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, _, err := decodeXMLText[float64]([]byte(attr.Value), fi)
+	if err != nil {
+		return err
+	}
+	*fi = Float(v)
+	return nil
+}
+
+// MarshalXML method for Float
+func (fi Float) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXML(e, start, encodeText(float64(fi)), true)
+}
+
+// MarshalXMLAttr method for Float
+func (fi Float) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return encodeXMLAttr(name, encodeText(float64(fi)), true)
+}
+
+// UnmarshalXML method for Bool,
+// empty elements and xsi:nil are the zero value
+func (fb *Bool) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v, _, err := decodeXML[bool](d, start, fb)
+	if err != nil {
+		return err
+	}
+	*fb = Bool(v)
+	return nil
+}
+
+// UnmarshalXMLAttr method for Bool,
+// empty attributes are the zero value
+func (fb *Bool) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, _, err := decodeXMLText[bool]([]byte(attr.Value), fb)
+	if err != nil {
+		return err
+	}
+	*fb = Bool(v)
+	return nil
+}
+
+// MarshalXML method for Bool
+func (fb Bool) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXML(e, start, encodeText(bool(fb)), true)
+}
+
+// MarshalXMLAttr method for Bool
+func (fb Bool) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return encodeXMLAttr(name, encodeText(bool(fb)), true)
+}
+
+// UnmarshalXML method for NullString,
+// empty elements and xsi:nil are not valid
+func (fs *NullString) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v, valid, err := decodeXML[string](d, start, fs)
+	if err != nil {
+		return err
+	}
+	*fs = NullString(null.NewString(v, valid))
+	return nil
+}
+
+// UnmarshalXMLAttr method for NullString,
+// empty attributes are not valid
+func (fs *NullString) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, valid, err := decodeXMLText[string]([]byte(attr.Value), fs)
+	if err != nil {
+		return err
+	}
+	*fs = NullString(null.NewString(v, valid))
+	return nil
+}
+
+// MarshalXML method for NullString,
+// invalid values are empty elements with xsi:nil="true"
+func (fs NullString) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXML(e, start, encodeText(fs.String), fs.Valid)
+}
+
+// MarshalXMLAttr method for NullString,
+// invalid values are omitted
+func (fs NullString) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return encodeXMLAttr(name, encodeText(fs.String), fs.Valid)
+}
+
+// UnmarshalXML method for NullInt,
+// empty elements and xsi:nil are not valid
+func (fi *NullInt) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v, valid, err := decodeXML[int64](d, start, fi)
+	if err != nil {
+		return err
+	}
+	*fi = NullInt(null.NewInt(v, valid))
+	return nil
+}
+
+// UnmarshalXMLAttr method for NullInt,
+// empty attributes are not valid
+func (fi *NullInt) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, valid, err := decodeXMLText[int64]([]byte(attr.Value), fi)
+	if err != nil {
+		return err
+	}
+	*fi = NullInt(null.NewInt(v, valid))
+	return nil
+}
+
+// MarshalXML method for NullInt,
+// invalid values are empty elements with xsi:nil="true"
+func (fi NullInt) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXML(e, start, encodeText(fi.Int64), fi.Valid)
+}
+
+// MarshalXMLAttr method for NullInt,
+// invalid values are omitted
+func (fi NullInt) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return encodeXMLAttr(name, encodeText(fi.Int64), fi.Valid)
+}
+
+// UnmarshalXML method for NullFloat,
+// empty elements and xsi:nil are not valid
+func (fi *NullFloat) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v, valid, err := decodeXML[float64](d, start, fi)
+	if err != nil {
+		return err
+	}
+	*fi = NullFloat(null.NewFloat(v, valid))
+	return nil
+}
+
+// UnmarshalXMLAttr method for NullFloat,
+// empty attributes are not valid
+func (fi *NullFloat) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, valid, err := decodeXMLText[float64]([]byte(attr.Value), fi)
+	if err != nil {
+		return err
+	}
+	*fi = NullFloat(null.NewFloat(v, valid))
+	return nil
+}
+
+// MarshalXML method for NullFloat,
+// invalid values are empty elements with xsi:nil="true"
+func (fi NullFloat) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXML(e, start, encodeText(fi.Float64), fi.Valid)
+}
+
+// MarshalXMLAttr method for NullFloat,
+// invalid values are omitted
+func (fi NullFloat) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return encodeXMLAttr(name, encodeText(fi.Float64), fi.Valid)
+}
+
+// UnmarshalXML method for NullBool,
+// empty elements and xsi:nil are not valid
+func (fb *NullBool) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v, valid, err := decodeXML[bool](d, start, fb)
+	if err != nil {
+		return err
+	}
+	*fb = NullBool(null.NewBool(v, valid))
+	return nil
+}
+
+// UnmarshalXMLAttr method for NullBool,
+// empty attributes are not valid
+func (fb *NullBool) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, valid, err := decodeXMLText[bool]([]byte(attr.Value), fb)
+	if err != nil {
+		return err
+	}
+	*fb = NullBool(null.NewBool(v, valid))
+	return nil
+}
+
+// MarshalXML method for NullBool,
+// invalid values are empty elements with xsi:nil="true"
+func (fb NullBool) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXML(e, start, encodeText(fb.Bool), fb.Valid)
+}
+
+// MarshalXMLAttr method for NullBool,
+// invalid values are omitted
+func (fb NullBool) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return encodeXMLAttr(name, encodeText(fb.Bool), fb.Valid)
+}
+
+// UnmarshalXML method for Value,
+// empty elements and xsi:nil are the zero value
+func (fv *Value[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v, _, err := decodeXML[T](d, start, fv)
+	if err != nil {
+		return err
+	}
+	fv.V = v
+	return nil
+}
+
+// UnmarshalXMLAttr method for Value,
+// empty attributes are the zero value
+func (fv *Value[T]) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, _, err := decodeXMLText[T]([]byte(attr.Value), fv)
+	if err != nil {
+		return err
+	}
+	fv.V = v
+	return nil
+}
+
+// MarshalXML method for Value
+func (fv Value[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXML(e, start, encodeText(fv.V), true)
+}
+
+// MarshalXMLAttr method for Value
+func (fv Value[T]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return encodeXMLAttr(name, encodeText(fv.V), true)
+}
+
+// UnmarshalXML method for Null,
+// empty elements and xsi:nil are not valid
+func (fn *Null[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v, valid, err := decodeXML[T](d, start, fn)
+	if err != nil {
+		return err
+	}
+	fn.V, fn.Valid = v, valid
+	return nil
+}
+
+// UnmarshalXMLAttr method for Null,
+// empty attributes are not valid
+func (fn *Null[T]) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, valid, err := decodeXMLText[T]([]byte(attr.Value), fn)
+	if err != nil {
+		return err
+	}
+	fn.V, fn.Valid = v, valid
+	return nil
+}
+
+// MarshalXML method for Null,
+// invalid values are empty elements with xsi:nil="true"
+func (fn Null[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXML(e, start, encodeText(fn.V), fn.Valid)
+}
+
+// MarshalXMLAttr method for Null,
+// invalid values are omitted
+func (fn Null[T]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return encodeXMLAttr(name, encodeText(fn.V), fn.Valid)
+}
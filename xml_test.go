@@ -0,0 +1,100 @@
+package fuzzy_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/mozey/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+// Interfaces must be implemented
+var (
+	_ xml.Unmarshaler     = (*fuzzy.NullString)(nil)
+	_ xml.UnmarshalerAttr = (*fuzzy.NullString)(nil)
+	_ xml.Marshaler       = fuzzy.NullString{}
+	_ xml.MarshalerAttr   = fuzzy.NullString{}
+	_ xml.Unmarshaler     = (*fuzzy.Value[int64])(nil)
+	_ xml.MarshalerAttr   = fuzzy.Null[bool]{}
+)
+
+type SupplierItem struct {
+	XMLName  xml.Name            `xml:"item"`
+	SKU      fuzzy.String        `xml:"sku,attr"`
+	Discount fuzzy.NullFloat     `xml:"discount,attr"`
+	Qty      fuzzy.Int           `xml:"qty"`
+	Price    fuzzy.Float         `xml:"price"`
+	Active   fuzzy.Bool          `xml:"active"`
+	Note     fuzzy.NullString    `xml:"note"`
+	Stock    fuzzy.NullInt       `xml:"stock"`
+	Taxed    fuzzy.NullBool      `xml:"taxed"`
+	Weight   fuzzy.Null[float64] `xml:"weight"`
+	Count    fuzzy.Value[int64]  `xml:"count"`
+}
+
+func TestUnmarshalXML(t *testing.T) {
+	b := []byte(`<item sku="123" discount="">
+		<qty>12.0</qty>
+		<price> 9.99 </price>
+		<active>Y</active>
+		<note></note>
+		<stock xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"/>
+		<taxed>0</taxed>
+		<weight xsi:nil="1"></weight>
+		<count>1e3</count>
+	</item>`)
+	item := SupplierItem{}
+	err := xml.Unmarshal(b, &item)
+	require.NoError(t, err)
+	require.Equal(t, SupplierItem{
+		XMLName: xml.Name{Local: "item"},
+		SKU:     "123",
+		Qty:     12,
+		Price:   9.99,
+		Active:  true,
+		Taxed:   fuzzy.NullBool(null.BoolFrom(false)),
+		Count:   fuzzy.Value[int64]{V: 1000},
+	}, item)
+
+	// Empty elements are the zero value for the other types
+	item = SupplierItem{Qty: 1}
+	err = xml.Unmarshal([]byte(`<item><qty/><note>a</note></item>`), &item)
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.Int(0), item.Qty)
+	require.Equal(t, fuzzy.NullString(null.StringFrom("a")), item.Note)
+
+	// Errors
+	err = xml.Unmarshal([]byte(`<item><qty>abc</qty></item>`), &item)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+	err = xml.Unmarshal([]byte(`<item><qty>1.5</qty></item>`), &item)
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.Int(1), item.Qty, "numbers must be truncated")
+	err = xml.Unmarshal([]byte(`<item discount="x"></item>`), &item)
+	require.ErrorIs(t, err, fuzzy.ErrInvalidNumberString)
+}
+
+func TestMarshalXML(t *testing.T) {
+	item := SupplierItem{
+		SKU:    "a&b",
+		Qty:    2,
+		Price:  1.5,
+		Active: true,
+		Note:   fuzzy.NullString(null.StringFrom("n")),
+		Taxed:  fuzzy.NullBool(null.BoolFrom(false)),
+		Count:  fuzzy.Value[int64]{V: 3},
+	}
+	b, err := xml.Marshal(item)
+	require.NoError(t, err)
+	nilAttr := ` xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"`
+	require.Equal(t, `<item sku="a&amp;b">`+
+		`<qty>2</qty><price>1.5</price><active>true</active><note>n</note>`+
+		`<stock`+nilAttr+`></stock><taxed>false</taxed>`+
+		`<weight`+nilAttr+`></weight><count>3</count></item>`, string(b))
+
+	// Round trip
+	decoded := SupplierItem{}
+	require.NoError(t, xml.Unmarshal(b, &decoded))
+	item.XMLName = xml.Name{Local: "item"}
+	require.Equal(t, item, decoded)
+}